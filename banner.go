@@ -0,0 +1,264 @@
+package reorderfuncs
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+// DefaultBannerTemplate is the section banner written before each group of
+// sorted functions when Options.Banners is set. The "%s" verb is replaced by
+// the title of the group, such as "Public Functions (ABC Order)".
+const DefaultBannerTemplate = `// ============================================================================
+//  %s
+// ============================================================================`
+
+const (
+	// bannerTitleVerb is the placeholder of the group title in banner templates.
+	bannerTitleVerb = "%s"
+	// exportedTitle is the banner title of the exported functions with ModeFuncs.
+	exportedTitle = "Public Functions (ABC Order)"
+	// methodsTitle is the banner title of the exported methods with ModeFuncs.
+	methodsTitle = "Methods (ABC Order)"
+	// unexportedTitle is the banner title of the unexported functions with ModeFuncs.
+	unexportedTitle = "Private Functions (ABC Order)"
+	// typesTitle is the banner title of the types, and their functions with ModeTypes.
+	typesTitle = "Types"
+	// constantsTitle is the banner title of the constants with SortDecls.
+	constantsTitle = "Constants"
+	// variablesTitle is the banner title of the variables with SortDecls.
+	variablesTitle = "Variables"
+)
+
+// ============================================================================
+//  Private Functions (ABC Order)
+// ============================================================================
+
+// appendBanner appends the section banner of the group of the function at the
+// given index when it starts a new group. The banner is separated from the
+// preceding content by the given number of blank lines.
+func appendBanner(outputLines []string, testFuncs []TestFunction, index, blankLines int, options Options) []string {
+	if !options.Banners {
+		return outputLines
+	}
+
+	title := groupTitle(testFuncs[index], options)
+	if index > 0 && groupTitle(testFuncs[index-1], options) == title {
+		return outputLines
+	}
+
+	outputLines = trimTrailingBlankLines(outputLines)
+	if len(outputLines) > 0 {
+		outputLines = appendBlankLines(outputLines, blankLines)
+	}
+
+	return append(outputLines, bannerLines(title, options)...)
+}
+
+// bannerComment returns the lines of the banner with the given title, without
+// the blank lines the template may start or end with.
+func bannerComment(title string, options Options) []string {
+	lines := bannerLines(title, options)
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+
+	return trimTrailingBlankLines(lines)
+}
+
+// bannerLines returns the lines of the banner with the given title.
+func bannerLines(title string, options Options) []string {
+	template := options.BannerTemplate
+	if template == "" {
+		template = DefaultBannerTemplate
+	}
+
+	return strings.Split(strings.ReplaceAll(template, bannerTitleVerb, title), "\n")
+}
+
+// bannerTitles returns every title groupTitle may return.
+func bannerTitles() []string {
+	titles := []string{exportedTitle, methodsTitle, unexportedTitle, typesTitle, constantsTitle, variablesTitle}
+	for _, entry := range kindNames {
+		titles = append(titles, entry.title)
+	}
+
+	return titles
+}
+
+// checkBannerTemplate returns an error wrapping ErrInvalidOption if a line of
+// the template is only the title verb, since such banners cannot be told apart
+// from the code.
+func checkBannerTemplate(options Options) error {
+	for _, line := range strings.Split(options.BannerTemplate, "\n") {
+		if strings.TrimSpace(line) == bannerTitleVerb {
+			return fmt.Errorf("%w: banner template %q has no text around %s",
+				ErrInvalidOption, options.BannerTemplate, bannerTitleVerb)
+		}
+	}
+
+	return nil
+}
+
+// coversComments reports whether every non-blank line of the range starting at
+// the given index belongs to the comments.
+func coversComments(lines []string, start int, comments map[int]bool) bool {
+	for offset, line := range lines {
+		if strings.TrimSpace(line) != "" && !comments[start+offset] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// detachBanners moves the first line of the comments owned by each
+// declaration, see buildCommentStarts, past the section banners preceding it
+// and the blank lines following them, so that the banners never move along
+// with the declarations and stay apart from the other comments.
+func detachBanners(lines []string, commentStarts map[int]int, options Options) map[int]int {
+	banners := findBanners(lines, options)
+
+	for start := range commentStarts {
+		for index, title := range banners {
+			end := index + len(bannerComment(title, options))
+			if end > start {
+				continue
+			}
+
+			for end < start && strings.TrimSpace(lines[end]) == "" {
+				end++
+			}
+
+			commentStarts[start] = max(commentStarts[start], end)
+		}
+	}
+
+	return commentStarts
+}
+
+// findBanners maps the index of the first line of each section banner in the
+// lines to its title. A banner is made of the exact lines of bannerComment for
+// one of the group titles, and of whole standalone comment groups only, see
+// isStandaloneComment, so that a doc comment or a comment within a function is
+// never taken for a banner. It returns an empty map if the lines do not parse.
+func findBanners(lines []string, options Options) map[int]string {
+	banners := make(map[int]string)
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "", strings.Join(lines, "\n"), parser.ParseComments)
+	if err != nil {
+		return banners
+	}
+
+	standalone := make(map[int]bool) // 0-based lines of the standalone comment groups
+	groupStarts := make(map[int]bool)
+	groupEnds := make(map[int]bool)
+
+	for _, group := range file.Comments {
+		if !isStandaloneComment(file, fset, group) {
+			continue
+		}
+
+		start, end := fset.Position(group.Pos()).Line-1, fset.Position(group.End()).Line-1 // Convert to 0-based
+		for line := start; line <= end; line++ {
+			standalone[line] = true
+		}
+
+		groupStarts[start] = true
+		groupEnds[end] = true
+	}
+
+	for start := range groupStarts {
+		for _, title := range bannerTitles() {
+			banner := bannerComment(title, options)
+			end := start + len(banner) - 1
+
+			if len(banner) == 0 || !groupEnds[end] || !slices.Equal(lines[start:end+1], banner) {
+				continue
+			}
+
+			if coversComments(lines[start:end+1], start, standalone) {
+				banners[start] = title
+
+				break
+			}
+		}
+	}
+
+	return banners
+}
+
+// groupTitle returns the banner title of the group of the function: the title
+// of its kind, or with ModeFuncs and ModeTypes whether it is exported or not,
+// the exported methods having their own group.
+// With ModeTypes, the types and their functions share a single group. The
+// general declarations are grouped by keyword.
+func groupTitle(testFunc TestFunction, options Options) string {
+	switch {
+	case testFunc.Token == token.TYPE:
+		return typesTitle
+	case testFunc.Token == token.CONST:
+		return constantsTitle
+	case testFunc.Token == token.VAR:
+		return variablesTitle
+	case options.Mode == ModeTests:
+		return testFunc.Kind.title()
+	case options.Mode == ModeTypes && testFunc.Type != "":
+		return typesTitle
+	case !token.IsExported(testFunc.Name):
+		return unexportedTitle
+	case testFunc.Receiver != "":
+		return methodsTitle
+	default:
+		return exportedTitle
+	}
+}
+
+// removeBanners removes the existing section banners, see findBanners, and the
+// blank lines following them, from the non-test lines so that they can be
+// regenerated. Only the banners of the groups of the test functions are
+// removed, the others are kept as any comment. The offsets of the test
+// functions are updated accordingly.
+func removeBanners(testFuncs []TestFunction, nonTestLines []string, options Options) ([]TestFunction, []string) {
+	banners := findBanners(nonTestLines, options)
+	kept := make([]string, 0, len(nonTestLines))
+	newIndex := make([]int, len(nonTestLines)+1)
+
+	titles := make(map[string]bool)
+	for _, testFunc := range testFuncs {
+		titles[groupTitle(testFunc, options)] = true
+	}
+
+	for index := 0; index < len(nonTestLines); {
+		title, ok := banners[index]
+		if !ok || !titles[title] {
+			newIndex[index] = len(kept)
+			kept = append(kept, nonTestLines[index])
+			index++
+
+			continue
+		}
+
+		end := index + len(bannerComment(title, options))
+		for end < len(nonTestLines) && strings.TrimSpace(nonTestLines[end]) == "" {
+			end++
+		}
+
+		for ; index < end; index++ {
+			newIndex[index] = len(kept)
+		}
+	}
+
+	newIndex[len(nonTestLines)] = len(kept)
+
+	moved := make([]TestFunction, len(testFuncs))
+	for i, testFunc := range testFuncs {
+		testFunc.Offset = newIndex[min(max(testFunc.Offset, 0), len(nonTestLines))]
+		moved[i] = testFunc
+	}
+
+	return moved, kept
+}
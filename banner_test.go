@@ -0,0 +1,183 @@
+package reorderfuncs
+
+import (
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+//	Private Functions (ABC Order)
+// ============================================================================
+
+func Test_appendBanner(t *testing.T) {
+	t.Parallel()
+
+	testFuncs := []TestFunction{
+		{Name: "TestAlpha", Kind: KindTest},
+		{Name: "TestBeta", Kind: KindTest},
+		{Name: "BenchmarkAlpha", Kind: KindBenchmark},
+	}
+	options := newOptions(WithBanners("// --- %s ---"))
+
+	assert.Equal(t, []string{"package x", "", "// --- Tests ---"},
+		appendBanner([]string{"package x", ""}, testFuncs, 0, 1, options))
+	assert.Equal(t, []string{"func TestAlpha() {}"},
+		appendBanner([]string{"func TestAlpha() {}"}, testFuncs, 1, 1, options), "same group has no banner")
+	assert.Equal(t, []string{"func TestBeta() {}", "", "", "// --- Benchmarks ---"},
+		appendBanner([]string{"func TestBeta() {}"}, testFuncs, 2, 2, options))
+	assert.Equal(t, []string{"package x"},
+		appendBanner([]string{"package x"}, testFuncs, 0, 1, newOptions()), "no banners by default")
+}
+
+func Test_bannerComment(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"// Tests"}, bannerComment("Tests", newOptions(WithBanners("\n// %s\n"))))
+	assert.Len(t, bannerComment("Tests", newOptions(WithBanners(""))), 3)
+}
+
+func Test_bannerTitles(t *testing.T) {
+	t.Parallel()
+
+	titles := bannerTitles()
+
+	assert.Contains(t, titles, "Tests")
+	assert.Contains(t, titles, "Fuzz Tests")
+	assert.Contains(t, titles, "Methods (ABC Order)")
+	assert.Contains(t, titles, "Private Functions (ABC Order)")
+	assert.Contains(t, titles, "Variables")
+}
+
+func Test_checkBannerTemplate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, checkBannerTemplate(newOptions()))
+	require.NoError(t, checkBannerTemplate(newOptions(WithBanners(""))))
+	require.NoError(t, checkBannerTemplate(newOptions(WithBanners("// %s"))))
+	require.ErrorIs(t, checkBannerTemplate(newOptions(WithBanners("// ==\n%s\n// =="))), ErrInvalidOption)
+	require.ErrorIs(t, checkBannerTemplate(newOptions(WithBanners(" %s "))), ErrInvalidOption)
+}
+
+func Test_detachBanners(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{
+		"package x",           // 0
+		"",                    // 1
+		"// Tests",            // 2
+		"",                    // 3
+		"// Floating comment", // 4
+		"",                    // 5
+		"func TestA() {}",     // 6
+		"",                    // 7
+		"// Tests",            // 8
+		"",                    // 9
+		"func TestB() {}",     // 10
+	}
+	options := newOptions(WithBanners("// %s"))
+
+	assert.Equal(t, map[int]int{6: 4, 10: 10}, detachBanners(lines, map[int]int{6: 1, 10: 7}, options),
+		"the banners and the blank lines following them are not owned")
+	assert.Equal(t, map[int]int{6: 1}, detachBanners(lines, map[int]int{6: 1}, newOptions(WithBanners(""))),
+		"other templates do not match")
+}
+
+func Test_findBanners(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{
+		"package x",        // 0
+		"",                 // 1
+		"// Tests",         // 2
+		"",                 // 3
+		"// Examples",      // 4
+		"var registry = 1", // 5
+		"",                 // 6
+		"// Helpers",       // 7
+		"// and more",      // 8
+		"",                 // 9
+		"func f() {",       // 10
+		"// Tests",         // 11
+		"}",                // 12
+		"",                 // 13
+		"// ==",            // 14
+		"",                 // 15
+		"// Benchmarks",    // 16
+		"",                 // 17
+		"// ==",            // 18
+		"",                 // 19
+		"var s = `",        // 20
+		"// Tests",         // 21
+		"`",                // 22
+		"",                 // 23
+	}
+
+	assert.Equal(t, map[int]string{2: "Tests", 16: "Benchmarks"}, findBanners(lines, newOptions(WithBanners("// %s"))),
+		"only whole standalone comment groups are banners")
+	assert.Equal(t, map[int]string{14: "Benchmarks"},
+		findBanners(lines, newOptions(WithBanners("// ==\n\n// %s\n\n// =="))), "a banner may span several comment groups")
+	assert.Empty(t, findBanners(lines[10:], newOptions(WithBanners("// %s"))), "the lines do not parse")
+}
+
+func Test_groupTitle(t *testing.T) {
+	t.Parallel()
+
+	funcs := newOptions(WithMode(ModeFuncs))
+
+	assert.Equal(t, "Benchmarks", groupTitle(TestFunction{Name: "BenchmarkFoo", Kind: KindBenchmark}, newOptions()))
+	assert.Equal(t, "Public Functions (ABC Order)", groupTitle(TestFunction{Name: "Describe"}, funcs))
+	assert.Equal(t, "Methods (ABC Order)", groupTitle(TestFunction{Name: "Area", Receiver: "Circle"}, funcs))
+	assert.Equal(t, "Private Functions (ABC Order)", groupTitle(TestFunction{Name: "describe"}, funcs))
+
+	assert.Equal(t, "Constants", groupTitle(TestFunction{Name: "limit", Token: token.CONST}, funcs))
+	assert.Equal(t, "Variables", groupTitle(TestFunction{Name: "cache", Token: token.VAR}, newOptions()))
+
+	types := newOptions(WithMode(ModeTypes))
+
+	assert.Equal(t, "Types", groupTitle(TestFunction{Name: "lock", Receiver: "Store", Type: "Store"}, types))
+	assert.Equal(t, "Public Functions (ABC Order)", groupTitle(TestFunction{Name: "Open"}, types))
+}
+
+func Test_removeBanners(t *testing.T) {
+	t.Parallel()
+
+	nonTestLines := []string{
+		"package x",
+		"",
+		"// --- Helpers ---",
+		"",
+		"func helper() {}",
+		"// --- Tests ---",
+		"",
+		"",
+	}
+	testFuncs := []TestFunction{{Name: "TestAlpha", Offset: 5}, {Name: "TestBeta", Offset: 8}}
+
+	options := newOptions(WithBanners("// --- %s ---"))
+
+	actualFuncs, actualLines := removeBanners(testFuncs, nonTestLines, options)
+
+	assert.Equal(t, []string{"package x", "", "// --- Helpers ---", "", "func helper() {}"}, actualLines,
+		"the banner of a group with no function is kept")
+	assert.Equal(t, 5, actualFuncs[0].Offset)
+	assert.Equal(t, 5, actualFuncs[1].Offset)
+
+	testFuncs = append(testFuncs, TestFunction{Name: "assertAlpha", Kind: KindHelper, Offset: 8})
+
+	actualFuncs, actualLines = removeBanners(testFuncs, nonTestLines, options)
+
+	assert.Equal(t, []string{"package x", "", "func helper() {}"}, actualLines)
+	assert.Equal(t, 3, actualFuncs[0].Offset)
+	assert.Equal(t, 3, actualFuncs[2].Offset)
+	assert.Equal(t, 5, testFuncs[0].Offset, "the given test functions are not modified")
+
+	docs := []string{"package x", "", "// --- Tests ---", "var registry = 1", "", "// --- Tests ---", ""}
+
+	_, actualLines = removeBanners(testFuncs, docs, options)
+
+	assert.Equal(t, []string{"package x", "", "// --- Tests ---", "var registry = 1", ""}, actualLines,
+		"a doc comment is never a banner")
+}
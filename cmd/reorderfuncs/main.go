@@ -5,12 +5,15 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
 
 	reorderfuncs "github.com/KEINOS/go-ReOrderFuncs"
 )
 
-var errUsage = errors.New(`usage: reorderfuncs <input file> [<output file>]`)
+var errUsage = errors.New(`usage: reorderfuncs [options] <input file> [<output file>]`)
 
 //nolint:gochecknoglobals // osExit and exitOnErr are for mocking in tests
 var (
@@ -26,21 +29,115 @@ var (
 )
 
 func main() {
-	flag.Parse()
+	var options reorderfuncs.Options
 
-	if flag.NArg() == 0 || flag.NArg() > 2 {
+	flags := newFlagSet(&options)
+
+	err := flags.Parse(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintf(os.Stdout, "%v\n\noptions:\n", errUsage)
+		flags.SetOutput(os.Stdout)
+		flags.PrintDefaults()
+		osExit(0)
+
+		return
+	}
+
+	if err != nil {
+		exitOnErr(fmt.Errorf("%w\n\n%w", err, errUsage))
+	}
+
+	if flags.NArg() == 0 || flags.NArg() > 2 {
 		exitOnErr(fmt.Errorf("missing or too many arguments\n\n%w", errUsage))
 	}
 
-	pathInput := flag.Arg(0)
+	pathInput := flags.Arg(0)
 	pathOutput := pathInput
 
-	if flag.NArg() > 1 {
-		pathOutput = flag.Arg(1)
+	if flags.NArg() > 1 {
+		pathOutput = flags.Arg(1)
 	}
 
-	err := reorderfuncs.Exec(pathInput, pathOutput)
+	err = reorderfuncs.Exec(pathInput, pathOutput, reorderfuncs.WithOptions(options))
 	if err != nil {
 		exitOnErr(err)
 	}
 }
+
+// newFlagSet creates the command-line flags that fill the given options.
+func newFlagSet(options *reorderfuncs.Options) *flag.FlagSet {
+	flags := flag.NewFlagSet("reorderfuncs", flag.ContinueOnError)
+	flags.SetOutput(io.Discard) // Errors are reported once by exitOnErr
+
+	flags.BoolVar(&options.Banners, "banners", false,
+		"write a section banner comment before each group of sorted functions")
+	flags.Func("banner-template", `section banner where %s is the group title and \n separates lines (implies -banners)`,
+		func(value string) error {
+			options.Banners = true
+			options.BannerTemplate = strings.ReplaceAll(value, `\n`, "\n")
+
+			return nil
+		})
+	flags.IntVar(&options.BlankLines, "blank-lines", 1,
+		"number of blank lines between the sorted functions")
+	flags.BoolVar(&options.CheckEquivalence, "check-equivalence", false,
+		"also verify that every doc comment stays with its declaration")
+	flags.Var(&options.Commented, "commented",
+		"what to do with commented-out functions (attach, keep, sort)")
+	flags.Var(&options.Floating, "floating",
+		"what to do with comments separated from a function by a blank line (attach, keep, previous)")
+	flags.BoolVar(&options.Gofmt, "gofmt", false,
+		"format the output like gofmt does")
+	flags.BoolVar(&options.GroupSuites, "group-suites", false,
+		"group testify suite methods under their receiver type, runner first")
+	flags.Var(&options.Kinds, "kinds",
+		"comma-separated kinds of functions to reorder, in group order (test,benchmark,fuzz,example,helper)")
+	flags.Func("marker", "write the sorted functions after this comment line (implies -placement after-marker)",
+		func(value string) error {
+			options.Marker = value
+			options.Placement = reorderfuncs.PlaceAfterMarker
+
+			return nil
+		})
+	flags.Var(&options.Mode, "mode",
+		"functions to reorder: tests, funcs for every function, exported first, or types for types followed by their functions")
+	flags.BoolFunc("natural", "sort names in natural order (Test_case2 before Test_case10)", func(value string) error {
+		natural, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid natural value %q: %w", value, err)
+		}
+
+		options.Compare = nil
+		if natural {
+			options.Compare = reorderfuncs.CompareNatural
+		}
+
+		return nil
+	})
+	flags.Var(&options.Placement, "placement",
+		"where to write the sorted functions (bottom, in-place, top, after-marker)")
+	flags.BoolVar(&options.MoveInit, "move-init", false,
+		"allow init functions to be reordered when they are matched")
+	flags.Var(&options.TestMain, "testmain",
+		"how to position TestMain (sorted, first, keep)")
+	flags.Var(&options.Spacing, "spacing",
+		"blank lines between the declarations: preserve, or normalize to exactly -blank-lines everywhere")
+	flags.BoolVar(&options.Strict, "strict", false,
+		"recognize functions the way go test does (name, receiver and signature)")
+	flags.BoolVar(&options.SortDecls, "sort-decls", false,
+		"with -mode funcs or types, move types, constants and variables before the functions and sort their groups")
+	flags.Var(&options.SortKey, "sort-key",
+		"comma-separated normalizations applied before sorting (fold-case,ignore-underscore,trim-prefix)")
+	flags.Func("perm", "permission of the output file in octal (default 0644)", func(value string) error {
+		mode, err := strconv.ParseUint(value, 8, 32)
+		if err != nil {
+			return fmt.Errorf("invalid permission %q: %w", value, err)
+		}
+
+		options.FileMode = os.FileMode(mode)
+
+		return nil
+	})
+
+	return flags
+}
@@ -3,9 +3,11 @@ package main
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"testing"
 
+	reorderfuncs "github.com/KEINOS/go-ReOrderFuncs"
 	"github.com/stretchr/testify/require"
 )
 
@@ -48,6 +50,56 @@ func Test_main(t *testing.T) {
 			args:         []string{"test_name", "non_existent_file.go"},
 			expectErrMsg: "open non_existent_file.go: no such file or directory",
 		},
+		{
+			name:         "unknown flag",
+			args:         []string{"test_name", "-unknown", "input.go"},
+			expectErrMsg: "flag provided but not defined: -unknown",
+		},
+		{
+			name:         "invalid placement",
+			args:         []string{"test_name", "-placement", "middle", "input.go"},
+			expectErrMsg: `unknown placement "middle"`,
+		},
+		{
+			name:         "invalid kind",
+			args:         []string{"test_name", "-kinds", "test,unknown", "input.go"},
+			expectErrMsg: `unknown kind "unknown"`,
+		},
+		{
+			name:         "invalid mode",
+			args:         []string{"test_name", "-mode", "layers", "input.go"},
+			expectErrMsg: `unknown mode "layers"`,
+		},
+		{
+			name:         "invalid commented-out function policy",
+			args:         []string{"test_name", "-commented", "remove", "input.go"},
+			expectErrMsg: `unknown commented-out function policy "remove"`,
+		},
+		{
+			name:         "invalid floating comment policy",
+			args:         []string{"test_name", "-floating", "detach", "input.go"},
+			expectErrMsg: `unknown floating comment policy "detach"`,
+		},
+		{
+			name:         "invalid spacing policy",
+			args:         []string{"test_name", "-spacing", "compact", "input.go"},
+			expectErrMsg: `unknown spacing policy "compact"`,
+		},
+		{
+			name:         "invalid TestMain policy",
+			args:         []string{"test_name", "-testmain", "last", "input.go"},
+			expectErrMsg: `unknown TestMain policy "last"`,
+		},
+		{
+			name:         "invalid sort key",
+			args:         []string{"test_name", "-sort-key", "fold-case,unknown", "input.go"},
+			expectErrMsg: `unknown sort key "unknown"`,
+		},
+		{
+			name:         "invalid permission",
+			args:         []string{"test_name", "-perm", "rwx", "input.go"},
+			expectErrMsg: `invalid permission "rwx"`,
+		},
 		{
 			name:         "empty paths",
 			args:         []string{"test_name", "", ""},
@@ -69,6 +121,29 @@ func Test_main(t *testing.T) {
 	}
 }
 
+//nolint:paralleltest // due to monkey patching global variables
+func Test_main_help(t *testing.T) {
+	originalOsExit := osExit
+
+	defer func() { osExit = originalOsExit }()
+
+	originalOsArgs := os.Args
+
+	defer func() { os.Args = originalOsArgs }()
+
+	exitedWithCode := -1
+
+	osExit = func(code int) {
+		exitedWithCode = code
+	}
+
+	os.Args = []string{"test_name", "-h"}
+
+	main()
+
+	require.Equal(t, 0, exitedWithCode, "help is not an error")
+}
+
 //nolint:paralleltest // due to monkey patching global variables
 func Test_exitOnErr(t *testing.T) {
 	originalOsExit := osExit
@@ -86,3 +161,63 @@ func Test_exitOnErr(t *testing.T) {
 	require.Equal(t, 1, exitedWithCode,
 		"expected os.Exit to be called with code 1")
 }
+
+func Test_newFlagSet(t *testing.T) {
+	t.Parallel()
+
+	var options reorderfuncs.Options
+
+	flags := newFlagSet(&options)
+	require.Equal(t, io.Discard, flags.Output(), "errors should only be reported by exitOnErr")
+
+	err := flags.Parse([]string{
+		"-banner-template", `// [%s]\n`,
+		"-blank-lines", "2",
+		"-check-equivalence",
+		"-commented", "sort",
+		"-floating", "keep",
+		"-gofmt",
+		"-group-suites",
+		"-kinds", "test,benchmark",
+		"-marker", "// Tests",
+		"-mode", "funcs",
+		"-natural",
+		"-placement", "in-place",
+		"-perm", "0600",
+		"-sort-key", "fold-case,trim-prefix",
+		"-sort-decls",
+		"-spacing", "normalize",
+		"-strict",
+		"-testmain", "first",
+		"-move-init",
+		"input.go", "output.go",
+	})
+	require.NoError(t, err)
+
+	require.True(t, options.Banners, "banner template should imply banners")
+	require.Equal(t, "// [%s]\n", options.BannerTemplate)
+	require.Equal(t, 2, options.BlankLines)
+	require.True(t, options.CheckEquivalence)
+	require.Equal(t, reorderfuncs.CommentedSort, options.Commented)
+	require.Equal(t, reorderfuncs.FloatingKeep, options.Floating)
+	require.True(t, options.Gofmt)
+	require.True(t, options.GroupSuites)
+	require.Equal(t, reorderfuncs.Kinds{reorderfuncs.KindTest, reorderfuncs.KindBenchmark}, options.Kinds)
+	require.Equal(t, "// Tests", options.Marker)
+	require.Equal(t, reorderfuncs.ModeFuncs, options.Mode)
+	require.NotNil(t, options.Compare, "natural flag should set the comparison")
+	require.Negative(t, options.Compare("Test_case2", "Test_case10"))
+	require.Equal(t, reorderfuncs.PlaceInPlace, options.Placement)
+	require.Equal(t, os.FileMode(0o600), options.FileMode)
+	require.Equal(t, reorderfuncs.SortKeyFoldCase|reorderfuncs.SortKeyTrimPrefix, options.SortKey)
+	require.True(t, options.SortDecls)
+	require.Equal(t, reorderfuncs.SpacingNormalize, options.Spacing)
+	require.True(t, options.Strict)
+	require.Equal(t, reorderfuncs.TestMainFirst, options.TestMain)
+	require.True(t, options.MoveInit)
+	require.Equal(t, []string{"input.go", "output.go"}, flags.Args())
+
+	require.NoError(t, flags.Parse([]string{"-natural=false"}))
+	require.Nil(t, options.Compare, "natural sort should be turned off")
+	require.Error(t, flags.Parse([]string{"-natural=maybe"}))
+}
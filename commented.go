@@ -0,0 +1,126 @@
+package reorderfuncs
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+)
+
+// commentedFunc is a function commented out between the top-level
+// declarations, such as // func TestOld(t *testing.T) { ... }.
+type commentedFunc struct {
+	// group is the comment holding the function.
+	group *ast.CommentGroup
+	// fn is the first function declaration parsed from the uncommented text.
+	fn *ast.FuncDecl
+}
+
+// ============================================================================
+//  Private Functions (ABC Order)
+// ============================================================================
+
+// buildCommentedDecls maps the identity of the commented-out functions moved
+// with CommentedSort to the function parsed from their uncommented text.
+func buildCommentedDecls(file *ast.File, fset *token.FileSet, options Options) map[string]*ast.FuncDecl {
+	commentedDecls := make(map[string]*ast.FuncDecl)
+	if options.Commented != CommentedSort {
+		return commentedDecls
+	}
+
+	testingName := testingImportName(file)
+
+	for _, commented := range findCommentedFuncs(file, fset, options) {
+		if options.match(commented.fn, testingName) {
+			commentedDecls[commented.identity(fset)] = commented.fn
+		}
+	}
+
+	return commentedDecls
+}
+
+// findCommentedFuncs returns the comment groups lying between the top-level
+// declarations whose whole text parses as function declarations, or nil with
+// CommentedAttach where they are plain comments.
+func findCommentedFuncs(file *ast.File, fset *token.FileSet, options Options) []commentedFunc {
+	if options.Commented == CommentedAttach {
+		return nil
+	}
+
+	var commentedFuncs []commentedFunc
+
+	for _, group := range file.Comments {
+		if group.Pos() < file.Name.End() || fset.Position(group.Pos()).Column != 1 || isInsideDecl(file, group) {
+			continue
+		}
+
+		if fn := parseCommentedFunc(group); fn != nil {
+			commentedFuncs = append(commentedFuncs, commentedFunc{group: group, fn: fn})
+		}
+	}
+
+	return commentedFuncs
+}
+
+// identity returns the identity of the commented-out function: its function
+// identity and line, such as "// TestOld 12", which never collides with the
+// identity of a declaration.
+func (c commentedFunc) identity(fset *token.FileSet) string {
+	return fmt.Sprintf("// %s %d", funcIdentity(c.fn), fset.Position(c.group.Pos()).Line)
+}
+
+// isInsideDecl reports whether the comment group lies inside a top-level
+// declaration, such as a comment in a function body.
+func isInsideDecl(file *ast.File, group *ast.CommentGroup) bool {
+	for _, decl := range file.Decls {
+		if decl.Pos() <= group.Pos() && group.End() <= decl.End() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseCommentedFunc parses the uncommented text of the comment group and
+// returns its first function declaration, or nil if the text is not made of
+// function declarations only.
+func parseCommentedFunc(group *ast.CommentGroup) *ast.FuncDecl {
+	file, err := parser.ParseFile(token.NewFileSet(), "", "package p\n"+group.Text(), parser.SkipObjectResolution)
+	if err != nil || len(file.Decls) == 0 {
+		return nil
+	}
+
+	for _, decl := range file.Decls {
+		if _, ok := decl.(*ast.FuncDecl); !ok {
+			return nil
+		}
+	}
+
+	fn, _ := file.Decls[0].(*ast.FuncDecl)
+
+	return fn
+}
+
+// topLevelDecls returns the top-level declarations of the file, in source
+// order, including the commented-out functions as *ast.BadDecl spanning their
+// comment group, so that they are units of their own rather than comments
+// preceding the next declaration.
+func topLevelDecls(file *ast.File, fset *token.FileSet, options Options) []ast.Decl {
+	commentedFuncs := findCommentedFuncs(file, fset, options)
+	if len(commentedFuncs) == 0 {
+		return file.Decls
+	}
+
+	decls := slices.Clone(file.Decls)
+	for _, commented := range commentedFuncs {
+		decls = append(decls, &ast.BadDecl{From: commented.group.Pos(), To: commented.group.End()})
+	}
+
+	slices.SortFunc(decls, func(a, b ast.Decl) int {
+		return cmp.Compare(a.Pos(), b.Pos())
+	})
+
+	return decls
+}
@@ -0,0 +1,132 @@
+package reorderfuncs
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+//	Helpers
+// ============================================================================
+
+// commentedSource is a test file with commented-out functions, one of them in
+// a function body.
+const commentedSource = `package main
+
+import "testing"
+
+func TestBravo(t *testing.T) {
+	// func TestInner(t *testing.T) {}
+}
+
+// func TestOld(t *testing.T) {
+// 	t.Skip()
+// }
+
+// TODO: func is a keyword.
+
+/* func (s *FooSuite) TestAncient() {} */
+
+func TestAlpha(t *testing.T) {}
+`
+
+// parseCommentedSource parses the commentedSource.
+func parseCommentedSource(t *testing.T) (*ast.File, *token.FileSet) {
+	t.Helper()
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "commented_test.go", commentedSource, parser.ParseComments)
+	require.NoError(t, err)
+
+	return file, fset
+}
+
+// ============================================================================
+//	Private Functions (ABC Order)
+// ============================================================================
+
+func Test_buildCommentedDecls(t *testing.T) {
+	t.Parallel()
+
+	file, fset := parseCommentedSource(t)
+
+	assert.Empty(t, buildCommentedDecls(file, fset, newOptions(WithCommented(CommentedKeep))))
+
+	commentedDecls := buildCommentedDecls(file, fset, newOptions(WithCommented(CommentedSort), WithStrict()))
+
+	require.Len(t, commentedDecls, 1, "suite methods are not strict tests")
+	assert.Equal(t, "TestOld", commentedDecls["// TestOld 9"].Name.Name)
+}
+
+func Test_findCommentedFuncs(t *testing.T) {
+	t.Parallel()
+
+	file, fset := parseCommentedSource(t)
+
+	assert.Empty(t, findCommentedFuncs(file, fset, Options{}), "plain comments by default")
+
+	commentedFuncs := findCommentedFuncs(file, fset, newOptions(WithCommented(CommentedKeep)))
+
+	require.Len(t, commentedFuncs, 2)
+	assert.Equal(t, "// TestOld 9", commentedFuncs[0].identity(fset))
+	assert.Equal(t, "// FooSuite.TestAncient 15", commentedFuncs[1].identity(fset))
+}
+
+func Test_parseCommentedFunc(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		comment string
+		expect  string
+	}{
+		{name: "line comments", comment: "// func TestOld(t *testing.T) {\n// }", expect: "TestOld"},
+		{name: "block comment", comment: "/*\nfunc helper() {}\n*/", expect: "helper"},
+		{name: "several functions", comment: "// func TestA() {}\n// func TestB() {}", expect: "TestA"},
+		{name: "prose", comment: "// TODO: rewrite TestOld.", expect: ""},
+		{name: "variable", comment: "// var x = 1", expect: ""},
+		{name: "function and variable", comment: "// func TestA() {}\n// var x = 1", expect: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			file, err := parser.ParseFile(token.NewFileSet(), "x.go", "package x\n"+test.comment, parser.ParseComments)
+			require.NoError(t, err)
+
+			fn := parseCommentedFunc(file.Comments[0])
+			if test.expect == "" {
+				assert.Nil(t, fn)
+
+				return
+			}
+
+			require.NotNil(t, fn)
+			assert.Equal(t, test.expect, fn.Name.Name)
+		})
+	}
+}
+
+func Test_topLevelDecls(t *testing.T) {
+	t.Parallel()
+
+	file, fset := parseCommentedSource(t)
+
+	assert.Equal(t, file.Decls, topLevelDecls(file, fset, Options{}))
+
+	decls := topLevelDecls(file, fset, newOptions(WithCommented(CommentedSort)))
+
+	require.Len(t, decls, 5)
+	assert.IsType(t, &ast.GenDecl{}, decls[0])
+	assert.IsType(t, &ast.FuncDecl{}, decls[1])
+	assert.IsType(t, &ast.BadDecl{}, decls[2])
+	assert.IsType(t, &ast.BadDecl{}, decls[3])
+	assert.IsType(t, &ast.FuncDecl{}, decls[4])
+}
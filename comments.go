@@ -0,0 +1,275 @@
+package reorderfuncs
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+// headerMarkers are the texts starting the comments of the file header, such
+// as license blocks, which never move.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var headerMarkers = []string{"Copyright", "SPDX-License-Identifier"}
+
+// ============================================================================
+//  Private Functions (ABC Order)
+// ============================================================================
+
+// buildCommentEnds maps the 0-based end line of each top-level declaration to
+// the last line of the floating comments following it, which move along with
+// it with FloatingPrevious. Declarations followed by no floating comment are
+// missing from the map.
+func buildCommentEnds(file *ast.File, fset *token.FileSet, options Options) map[int]int {
+	commentEnds := make(map[int]int)
+	if options.Floating != FloatingPrevious {
+		return commentEnds
+	}
+
+	prevEnd := endLineWithComments(file, fset, fset.Position(file.Name.End()).Line)
+	prevLine := -1 // 0-based end line of the previous moveable declaration
+
+	for _, decl := range topLevelDecls(file, fset, options) {
+		if prevLine >= 0 {
+			if end := headerEnd(file, fset, prevEnd, docStart(decl, fset)); end > prevEnd {
+				commentEnds[prevLine] = end - 1 // Convert to the 0-based last line
+			}
+		}
+
+		if !isImportDecl(decl) {
+			prevLine = fset.Position(decl.End()).Line - 1 // Convert to 0-based
+		}
+
+		prevEnd = endLineWithComments(file, fset, fset.Position(decl.End()).Line)
+	}
+
+	return commentEnds
+}
+
+// buildCommentStarts maps the 0-based start line of each top-level declaration
+// to the first line following the previous declaration, or the package clause.
+// The comments and blank lines in between precede the declaration and move
+// along with it. Comment groups are located from the AST, so every comment
+// form, such as multi-line /* ... */ blocks, is attached exactly.
+//
+// The file header, the comments between the package clause or the imports and
+// the first other declaration, is anchored at the top: the first declaration
+// only owns its doc comment, or only the declaration directives ending it if
+// it is a license or a file directive, see classifyDirective.
+// Unless the Floating policy is FloatingAttach, the other declarations are
+// treated the same way and the floating comments never move along with the
+// following declaration. Unless the Commented policy is CommentedAttach, the
+// commented-out functions are declarations of their own, see topLevelDecls.
+func buildCommentStarts(file *ast.File, fset *token.FileSet, options Options) map[int]int {
+	commentStarts := make(map[int]int, len(file.Decls))
+	prevEnd := endLineWithComments(file, fset, fset.Position(file.Name.End()).Line)
+	inHeader := true
+
+	for _, decl := range topLevelDecls(file, fset, options) {
+		start := fset.Position(decl.Pos()).Line - 1 // Convert to 0-based
+		commentStarts[start] = prevEnd              // First line after the 1-based end line
+
+		if (inHeader || options.Floating != FloatingAttach) && !isImportDecl(decl) {
+			commentStarts[start] = headerEnd(file, fset, prevEnd, docStart(decl, fset))
+			inHeader = false
+		}
+
+		prevEnd = endLineWithComments(file, fset, fset.Position(decl.End()).Line)
+	}
+
+	return commentStarts
+}
+
+// docStart returns the 0-based first line of the doc comment of the
+// declaration, or of the declaration itself if it has no doc comment. If the
+// doc comment belongs to the file header, only the declaration directives
+// ending it, such as //nolint, are part of the declaration.
+func docStart(decl ast.Decl, fset *token.FileSet) int {
+	var doc *ast.CommentGroup
+
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		doc = decl.Doc
+	case *ast.GenDecl:
+		doc = decl.Doc
+	}
+
+	if doc == nil {
+		return fset.Position(decl.Pos()).Line - 1
+	}
+
+	if isHeaderComment(doc) {
+		if start, ok := declDirectivesStart(doc, fset); ok {
+			return start
+		}
+
+		return fset.Position(decl.Pos()).Line - 1
+	}
+
+	return fset.Position(doc.Pos()).Line - 1
+}
+
+// endLineWithComments returns the last line of the comment groups starting on
+// the given line, such as a trailing comment spanning several lines, or the
+// line itself. Lines are 1-based.
+func endLineWithComments(file *ast.File, fset *token.FileSet, line int) int {
+	end := line
+
+	for _, group := range file.Comments {
+		if fset.Position(group.Pos()).Line == line {
+			end = max(end, fset.Position(group.End()).Line)
+		}
+	}
+
+	return end
+}
+
+// findMarker returns the index of the placement marker comment in the non-test
+// lines, or -1 if it is not found. Only the comments starting at column 1
+// outside of any declaration are markers, so that a matching line within a
+// function body or a string literal is never taken for the marker.
+func findMarker(nonTestLines []string, options Options) int {
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "", strings.Join(nonTestLines, "\n"), parser.ParseComments)
+	if err != nil {
+		return -1
+	}
+
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			position := fset.Position(comment.Pos())
+			if position.Column != 1 || !options.isMarker(nonTestLines[position.Line-1]) {
+				continue
+			}
+
+			if !withinDecl(file, comment.Pos()) {
+				return position.Line - 1 // Convert to 0-based
+			}
+		}
+	}
+
+	return -1
+}
+
+// findTrailerStart returns the index of the first line of the file trailer in
+// the non-test lines: the comments following the last declaration, such as
+// "// end of tests", which stay at the end of the file. The trailer must also
+// follow every test function found in the source. It returns the number of
+// lines if there is no trailer or if the non-test lines do not parse.
+func findTrailerStart(testFuncs []TestFunction, nonTestLines []string) int {
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "", strings.Join(nonTestLines, "\n"), parser.ParseComments)
+	if err != nil {
+		return len(nonTestLines)
+	}
+
+	lastLine := fset.Position(file.Name.End()).Line
+	if len(file.Decls) > 0 {
+		lastLine = fset.Position(file.Decls[len(file.Decls)-1].End()).Line
+	}
+
+	minStart := 0
+	for _, testFunc := range testFuncs {
+		minStart = max(minStart, testFunc.Offset)
+	}
+
+	for _, group := range file.Comments {
+		start := fset.Position(group.Pos()).Line - 1 // Convert to 0-based
+		if start >= lastLine && start >= minStart {
+			return start
+		}
+	}
+
+	return len(nonTestLines)
+}
+
+// headerEnd returns the 0-based line following the last comment group lying
+// between the from and to 0-based lines, or from if there is none. These
+// comments are detached from the declaration starting at the to line, such as
+// the file header before the first declaration. A comment group ending past
+// the to line, such as a license followed by the directives of the
+// declaration, ends at the to line.
+func headerEnd(file *ast.File, fset *token.FileSet, from, to int) int {
+	end := from
+
+	for _, group := range file.Comments {
+		line := fset.Position(group.Pos()).Line - 1 // Convert to 0-based
+		if line >= from && line < to {
+			end = max(end, min(fset.Position(group.End()).Line, to)) // First line after the 1-based end line
+		}
+	}
+
+	return end
+}
+
+// isHeaderComment reports whether the comment group belongs to the file header:
+// it starts with a license line, such as "// Copyright 2025 The Authors" or
+// "/* SPDX-License-Identifier: MIT */", or holds a file directive like
+// //go:build. A doc comment merely mentioning the copyright is not a header.
+func isHeaderComment(group *ast.CommentGroup) bool {
+	for _, comment := range group.List {
+		if classifyDirective(comment.Text) == directiveFile {
+			return true
+		}
+	}
+
+	text := strings.TrimPrefix(strings.TrimPrefix(group.List[0].Text, "//"), "/*")
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "*"))
+		if line == "" {
+			continue
+		}
+
+		for _, marker := range headerMarkers {
+			if strings.HasPrefix(line, marker) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return false
+}
+
+// isImportDecl reports whether the declaration is an import declaration.
+func isImportDecl(decl ast.Decl) bool {
+	genDecl, ok := decl.(*ast.GenDecl)
+
+	return ok && genDecl.Tok == token.IMPORT
+}
+
+// isStandaloneComment reports whether the comment group stands on its own: it
+// starts at column 1 outside of any declaration and it is not the doc comment
+// of the package or of a declaration.
+func isStandaloneComment(file *ast.File, fset *token.FileSet, group *ast.CommentGroup) bool {
+	if fset.Position(group.Pos()).Column != 1 || group == file.Doc || withinDecl(file, group.Pos()) {
+		return false
+	}
+
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Doc == group {
+				return false
+			}
+		case *ast.GenDecl:
+			if decl.Doc == group {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// withinDecl reports whether the position lies within a top-level declaration.
+func withinDecl(file *ast.File, pos token.Pos) bool {
+	return slices.ContainsFunc(file.Decls, func(decl ast.Decl) bool {
+		return decl.Pos() <= pos && pos < decl.End()
+	})
+}
@@ -0,0 +1,180 @@
+package reorderfuncs
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+)
+
+// SortKey is a set of normalizations applied to function names before they are
+// compared. Flags can be combined with the bitwise OR operator. It implements
+// flag.Value as a comma-separated list of names, e.g. "fold-case,trim-prefix".
+type SortKey uint
+
+const (
+	// SortKeyFoldCase compares names case-insensitively.
+	SortKeyFoldCase SortKey = 1 << iota
+	// SortKeyIgnoreUnderscore ignores "_" separators in names.
+	SortKeyIgnoreUnderscore
+	// SortKeyTrimPrefix ignores the kind prefix of names, such as "Test" or
+	// "Benchmark_".
+	SortKeyTrimPrefix
+)
+
+// sortKeyNames lists the sort key flags with their command-line names in the
+// order they are applied.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var sortKeyNames = []struct {
+	key  SortKey
+	name string
+}{
+	{key: SortKeyTrimPrefix, name: "trim-prefix"},
+	{key: SortKeyIgnoreUnderscore, name: "ignore-underscore"},
+	{key: SortKeyFoldCase, name: "fold-case"},
+}
+
+// ============================================================================
+//  Public Functions (ABC Order)
+// ============================================================================
+
+// CompareNatural compares two names in natural order, where runs of digits are
+// compared by their numeric value. For example "Test_case2" sorts before
+// "Test_case10" and "TestV1_9" before "TestV1_10".
+//
+// Names that only differ by leading zeros, such as "Test_01" and "Test_1", are
+// ordered by plain byte comparison to keep the result deterministic.
+func CompareNatural(a, b string) int {
+	if result := compareNaturalRuns(a, b); result != 0 {
+		return result
+	}
+
+	return strings.Compare(a, b)
+}
+
+// ============================================================================
+//  Methods (ABC Order)
+// ============================================================================
+
+// Apply returns the normalized form of the name used as the sort key.
+func (k SortKey) Apply(name string) string {
+	if k&SortKeyTrimPrefix != 0 {
+		name = trimKindPrefix(name)
+	}
+
+	if k&SortKeyIgnoreUnderscore != 0 {
+		name = strings.ReplaceAll(name, "_", "")
+	}
+
+	if k&SortKeyFoldCase != 0 {
+		name = strings.ToLower(name)
+	}
+
+	return name
+}
+
+// Set implements flag.Value. It parses a comma-separated list of sort key names.
+// An empty string clears all the flags.
+func (k *SortKey) Set(value string) error {
+	var key SortKey
+
+	for name := range strings.SplitSeq(value, ",") {
+		flag, err := parseSortKeyName(strings.TrimSpace(name))
+		if err != nil {
+			return err
+		}
+
+		key |= flag
+	}
+
+	*k = key
+
+	return nil
+}
+
+// String implements fmt.Stringer and flag.Value.
+func (k SortKey) String() string {
+	names := make([]string, 0, len(sortKeyNames))
+
+	for _, entry := range sortKeyNames {
+		if k&entry.key != 0 {
+			names = append(names, entry.name)
+		}
+	}
+
+	return strings.Join(names, ",")
+}
+
+// ============================================================================
+//  Private Functions (ABC Order)
+// ============================================================================
+
+// compareDigits compares two runs of ASCII digits by their numeric value
+// without converting them, so arbitrary long runs never overflow.
+func compareDigits(numA, numB string) int {
+	numA = strings.TrimLeft(numA, "0")
+	numB = strings.TrimLeft(numB, "0")
+
+	if result := cmp.Compare(len(numA), len(numB)); result != 0 {
+		return result
+	}
+
+	return strings.Compare(numA, numB)
+}
+
+// compareNaturalRuns compares two names run by run, treating runs of digits as
+// numbers and any other byte as is.
+func compareNaturalRuns(a, b string) int {
+	for a != "" && b != "" {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			numA, restA := splitDigits(a)
+			numB, restB := splitDigits(b)
+
+			if result := compareDigits(numA, numB); result != 0 {
+				return result
+			}
+
+			a, b = restA, restB
+
+			continue
+		}
+
+		if a[0] != b[0] {
+			return cmp.Compare(a[0], b[0])
+		}
+
+		a, b = a[1:], b[1:]
+	}
+
+	return cmp.Compare(len(a), len(b))
+}
+
+// isDigit reports whether the byte is an ASCII digit.
+func isDigit(char byte) bool {
+	return '0' <= char && char <= '9'
+}
+
+// parseSortKeyName returns the sort key flag of the given command-line name.
+func parseSortKeyName(name string) (SortKey, error) {
+	if name == "" {
+		return 0, nil
+	}
+
+	for _, entry := range sortKeyNames {
+		if entry.name == name {
+			return entry.key, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: unknown sort key %q", ErrInvalidOption, name)
+}
+
+// splitDigits splits the leading run of ASCII digits from the rest of the name.
+func splitDigits(name string) (string, string) {
+	end := 0
+	for end < len(name) && isDigit(name[end]) {
+		end++
+	}
+
+	return name[:end], name[end:]
+}
@@ -0,0 +1,113 @@
+package reorderfuncs
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+//	Public Functions (ABC Order)
+// ============================================================================
+
+func TestCompareNatural(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		a      string
+		b      string
+		expect int
+	}{
+		{name: "equal", a: "Test_case1", b: "Test_case1", expect: 0},
+		{name: "single vs double digit", a: "Test_case2", b: "Test_case10", expect: -1},
+		{name: "double vs single digit", a: "Test_case10", b: "Test_case2", expect: 1},
+		{name: "digits in the middle", a: "TestV1_9", b: "TestV1_10", expect: -1},
+		{name: "plain text", a: "Test_alpha", b: "Test_beta", expect: -1},
+		{name: "prefix is shorter", a: "Test_case", b: "Test_case1", expect: -1},
+		{name: "digit vs letter", a: "Test_1", b: "Test_a", expect: -1},
+		{name: "leading zeros tie-break", a: "Test_01", b: "Test_1", expect: -1},
+		{name: "leading zeros same value", a: "Test_002x", b: "Test_2y", expect: -1},
+		{name: "long digit runs", a: "Test_99999999999999999999", b: "Test_100000000000000000000", expect: -1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expect, CompareNatural(test.a, test.b))
+			assert.Equal(t, -test.expect, CompareNatural(test.b, test.a), "comparison should be antisymmetric")
+		})
+	}
+}
+
+func TestCompareNatural_sort(t *testing.T) {
+	t.Parallel()
+
+	names := []string{"Test_case10", "Test_case2", "Test_case1", "TestV1_10", "TestV1_9"}
+
+	slices.SortFunc(names, CompareNatural)
+
+	expect := []string{"TestV1_9", "TestV1_10", "Test_case1", "Test_case2", "Test_case10"}
+	assert.Equal(t, expect, names)
+}
+
+func TestSortKey_Apply(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		key    SortKey
+		input  string
+		expect string
+	}{
+		{name: "no normalization", key: 0, input: "Test_Foo_bar", expect: "Test_Foo_bar"},
+		{name: "fold case", key: SortKeyFoldCase, input: "Test_Foo_bar", expect: "test_foo_bar"},
+		{name: "ignore underscore", key: SortKeyIgnoreUnderscore, input: "Test_Foo_bar", expect: "TestFoobar"},
+		{name: "trim prefix with underscore", key: SortKeyTrimPrefix, input: "Test_Foo_bar", expect: "Foo_bar"},
+		{name: "trim prefix without underscore", key: SortKeyTrimPrefix, input: "TestFoo_bar", expect: "Foo_bar"},
+		{
+			name:   "all normalizations",
+			key:    SortKeyFoldCase | SortKeyIgnoreUnderscore | SortKeyTrimPrefix,
+			input:  "Test_Foo_bar",
+			expect: "foobar",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expect, test.key.Apply(test.input))
+		})
+	}
+}
+
+func TestSortKey_Set(t *testing.T) {
+	t.Parallel()
+
+	var key SortKey
+
+	require.NoError(t, key.Set("fold-case, ignore-underscore"))
+	assert.Equal(t, SortKeyFoldCase|SortKeyIgnoreUnderscore, key)
+
+	require.NoError(t, key.Set(""), "empty value should clear the flags")
+	assert.Equal(t, SortKey(0), key)
+
+	err := key.Set("trim-prefix,unknown")
+
+	require.ErrorIs(t, err, ErrInvalidOption)
+	assert.Contains(t, err.Error(), `unknown sort key "unknown"`)
+	assert.Equal(t, SortKey(0), key, "key should be unchanged on error")
+}
+
+func TestSortKey_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, SortKey(0).String())
+	assert.Equal(t, "fold-case", SortKeyFoldCase.String())
+	assert.Equal(t, "trim-prefix,ignore-underscore,fold-case",
+		(SortKeyFoldCase | SortKeyIgnoreUnderscore | SortKeyTrimPrefix).String())
+}
@@ -0,0 +1,198 @@
+package reorderfuncs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"slices"
+)
+
+// Ranks of the classes of declarations with Options.SortDecls.
+const (
+	rankTypes = iota
+	rankConsts
+	rankVars
+	rankFuncs
+)
+
+// specRange is the range of lines, 0-based and inclusive, of a spec in a
+// grouped declaration, including its doc comment.
+type specRange struct {
+	name  string
+	start int
+	end   int
+}
+
+// ============================================================================
+//  Private Functions (ABC Order)
+// ============================================================================
+
+// buildGenDecls maps the identity of the moved general declarations to their
+// declaration.
+func buildGenDecls(file *ast.File, fset *token.FileSet, options Options) map[string]*ast.GenDecl {
+	genDecls := make(map[string]*ast.GenDecl)
+
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+
+		if identity := genDeclIdentity(genDecl, fset, options); identity != "" {
+			genDecls[identity] = genDecl
+		}
+	}
+
+	return genDecls
+}
+
+// firstSpecName returns the name of the first type, constant or variable of
+// the declaration.
+func firstSpecName(decl *ast.GenDecl) string {
+	if len(decl.Specs) == 0 {
+		return ""
+	}
+
+	switch spec := decl.Specs[0].(type) {
+	case *ast.TypeSpec:
+		return spec.Name.Name
+	case *ast.ValueSpec:
+		return spec.Names[0].Name
+	default:
+		return ""
+	}
+}
+
+// genDeclIdentity returns the identity of a general declaration that is moved,
+// or an empty string if it is not moved. Type declarations are identified by
+// the name of their first type. Constant and variable declarations also carry
+// their keyword and line, such as "var _ 12", since blank names may be
+// declared several times.
+func genDeclIdentity(decl *ast.GenDecl, fset *token.FileSet, options Options) string {
+	if len(decl.Specs) == 0 {
+		return ""
+	}
+
+	switch {
+	case decl.Tok == token.TYPE && (options.sortsDecls() || options.Mode == ModeTypes):
+		return typeDeclName(decl)
+	case (decl.Tok == token.CONST || decl.Tok == token.VAR) && options.sortsDecls():
+		return fmt.Sprintf("%s %s %d", decl.Tok, firstSpecName(decl), fset.Position(decl.Pos()).Line)
+	default:
+		return ""
+	}
+}
+
+// isSortableBlock reports whether the specs of the grouped declaration can be
+// sorted. Constant blocks using iota or implicit repetition are not sortable
+// since the order of their specs is semantic.
+func isSortableBlock(decl *ast.GenDecl) bool {
+	if !decl.Lparen.IsValid() || len(decl.Specs) < 2 {
+		return false
+	}
+
+	switch decl.Tok {
+	case token.VAR:
+		return true
+	case token.CONST:
+		for _, spec := range decl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok || len(valueSpec.Values) == 0 || usesIota(valueSpec) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return false
+	}
+}
+
+// sortSpecLines returns a copy of the lines where the specs of the grouped
+// constant and variable declarations are sorted by name, within each run of
+// specs not separated by blank lines or comments. The lines of the other
+// declarations do not move.
+func sortSpecLines(lines []string, file *ast.File, fset *token.FileSet, options Options) []string {
+	sorted := slices.Clone(lines)
+
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || !isSortableBlock(genDecl) {
+			continue
+		}
+
+		for _, run := range specRuns(genDecl, fset) {
+			start := run[0].start
+
+			slices.SortStableFunc(run, func(a, b specRange) int {
+				return options.compare(a.name, b.name)
+			})
+
+			for _, spec := range run {
+				start += copy(sorted[start:], lines[spec.start:spec.end+1])
+			}
+		}
+	}
+
+	return sorted
+}
+
+// specRuns returns the runs of adjacent specs of the grouped declaration. Specs
+// separated by blank lines or comments belong to different runs. It returns
+// nil if specs share a line, since they cannot be moved apart.
+func specRuns(decl *ast.GenDecl, fset *token.FileSet) [][]specRange {
+	var runs [][]specRange
+
+	prevEnd := -1
+
+	for _, spec := range decl.Specs {
+		valueSpec, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			return nil
+		}
+
+		current := specRange{
+			name:  valueSpec.Names[0].Name,
+			start: fset.Position(valueSpec.Pos()).Line - 1,
+			end:   fset.Position(valueSpec.End()).Line - 1,
+		}
+
+		if valueSpec.Doc != nil {
+			current.start = fset.Position(valueSpec.Doc.Pos()).Line - 1
+		}
+
+		if valueSpec.Comment != nil {
+			current.end = max(current.end, fset.Position(valueSpec.Comment.End()).Line-1)
+		}
+
+		switch {
+		case current.start <= prevEnd:
+			return nil
+		case current.start == prevEnd+1 && len(runs) > 0:
+			runs[len(runs)-1] = append(runs[len(runs)-1], current)
+		default:
+			runs = append(runs, []specRange{current})
+		}
+
+		prevEnd = current.end
+	}
+
+	return runs
+}
+
+// usesIota reports whether the values of the spec refer to iota.
+func usesIota(spec *ast.ValueSpec) bool {
+	found := false
+
+	for _, value := range spec.Values {
+		ast.Inspect(value, func(node ast.Node) bool {
+			if ident, ok := node.(*ast.Ident); ok && ident.Name == "iota" {
+				found = true
+			}
+
+			return !found
+		})
+	}
+
+	return found
+}
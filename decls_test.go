@@ -0,0 +1,172 @@
+package reorderfuncs
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+//	Helpers
+// ============================================================================
+
+// parseGenDecl parses a single general declaration.
+func parseGenDecl(t *testing.T, decl string) (*ast.GenDecl, *token.FileSet) {
+	t.Helper()
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "decl.go", "package x\n"+decl, parser.ParseComments)
+	require.NoError(t, err)
+
+	genDecl, ok := file.Decls[0].(*ast.GenDecl)
+	require.True(t, ok, "declaration should be a general declaration")
+
+	return genDecl, fset
+}
+
+// ============================================================================
+//	Private Functions (ABC Order)
+// ============================================================================
+
+func Test_firstSpecName(t *testing.T) {
+	t.Parallel()
+
+	typeDecl, _ := parseGenDecl(t, "type (\n\tFoo int\n\tBar int\n)")
+	constDecl, _ := parseGenDecl(t, "const a, b = 1, 2")
+	importDecl, _ := parseGenDecl(t, `import "fmt"`)
+
+	assert.Equal(t, "Foo", firstSpecName(typeDecl))
+	assert.Equal(t, "a", firstSpecName(constDecl))
+	assert.Empty(t, firstSpecName(importDecl))
+	assert.Empty(t, firstSpecName(&ast.GenDecl{Tok: token.VAR}))
+}
+
+func Test_genDeclIdentity(t *testing.T) {
+	t.Parallel()
+
+	varDecl, fset := parseGenDecl(t, "var _ = 1")
+	typeDecl, _ := parseGenDecl(t, "type Foo int")
+	importDecl, _ := parseGenDecl(t, `import "fmt"`)
+	sortDecls := newOptions(WithSortDecls(), WithMode(ModeFuncs))
+
+	assert.Equal(t, "var _ 2", genDeclIdentity(varDecl, fset, sortDecls))
+	assert.Equal(t, "Foo", genDeclIdentity(typeDecl, fset, sortDecls))
+	assert.Equal(t, "Foo", genDeclIdentity(typeDecl, fset, newOptions(WithMode(ModeTypes))))
+	assert.Empty(t, genDeclIdentity(varDecl, fset, newOptions(WithMode(ModeTypes))), "values move with SortDecls only")
+	assert.Empty(t, genDeclIdentity(typeDecl, fset, newOptions()))
+	assert.Empty(t, genDeclIdentity(varDecl, fset, newOptions(WithSortDecls())), "nothing moves with ModeTests")
+	assert.Empty(t, genDeclIdentity(importDecl, fset, sortDecls), "imports never move")
+}
+
+func Test_isSortableBlock(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		decl   string
+		expect bool
+	}{
+		{name: "var block", decl: "var (\n\tb = 2\n\ta = 1\n)", expect: true},
+		{name: "const block", decl: "const (\n\tb = 2\n\ta = 1\n)", expect: true},
+		{name: "iota", decl: "const (\n\ta = iota\n\tb\n)", expect: false},
+		{name: "iota in expression", decl: "const (\n\ta = 1 << iota\n\tb = 1 << iota\n)", expect: false},
+		{name: "implicit repetition", decl: "const (\n\ta int = 1\n\tb\n)", expect: false},
+		{name: "single spec", decl: "var (\n\ta = 1\n)", expect: false},
+		{name: "ungrouped", decl: "var a = 1", expect: false},
+		{name: "type block", decl: "type (\n\tB int\n\tA int\n)", expect: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			decl, _ := parseGenDecl(t, test.decl)
+
+			assert.Equal(t, test.expect, isSortableBlock(decl))
+		})
+	}
+}
+
+func Test_sortSpecLines(t *testing.T) {
+	t.Parallel()
+
+	source := `package x
+
+var (
+	// zulu is documented.
+	zulu  = 26
+	alpha = 1 // trailing comment
+
+	mike = 13
+	bravo = 2
+)
+
+const (
+	b = iota
+	a
+)
+`
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "decl.go", source, parser.ParseComments)
+	require.NoError(t, err)
+
+	lines := strings.Split(source, "\n")
+	actual := sortSpecLines(lines, file, fset, newOptions())
+
+	expect := `package x
+
+var (
+	alpha = 1 // trailing comment
+	// zulu is documented.
+	zulu  = 26
+
+	bravo = 2
+	mike = 13
+)
+
+const (
+	b = iota
+	a
+)
+`
+	assert.Equal(t, expect, strings.Join(actual, "\n"))
+	assert.Equal(t, source, strings.Join(lines, "\n"), "the given lines are not modified")
+}
+
+func Test_specRuns(t *testing.T) {
+	t.Parallel()
+
+	decl, fset := parseGenDecl(t, "var (\n\tb = 2\n\n\t// a is documented.\n\ta = 1\n\tc = 3\n)")
+
+	assert.Equal(t, [][]specRange{
+		{{name: "b", start: 2, end: 2}},
+		{{name: "a", start: 4, end: 5}, {name: "c", start: 6, end: 6}},
+	}, specRuns(decl, fset))
+
+	sameLine, fset := parseGenDecl(t, "var (\n\tb = 2; a = 1\n)")
+
+	assert.Nil(t, specRuns(sameLine, fset), "specs sharing a line cannot be sorted")
+}
+
+func Test_usesIota(t *testing.T) {
+	t.Parallel()
+
+	withIota, _ := parseGenDecl(t, "const a = 1 << (iota * 10)")
+	withoutIota, _ := parseGenDecl(t, "const a = 1 << 10")
+
+	specWithIota, ok := withIota.Specs[0].(*ast.ValueSpec)
+	require.True(t, ok)
+
+	specWithoutIota, ok := withoutIota.Specs[0].(*ast.ValueSpec)
+	require.True(t, ok)
+
+	assert.True(t, usesIota(specWithIota))
+	assert.False(t, usesIota(specWithoutIota))
+}
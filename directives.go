@@ -0,0 +1,69 @@
+package reorderfuncs
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// directiveKind classifies the directive comments, such as //go:noinline.
+type directiveKind int
+
+const (
+	// directiveNone is a plain comment, not a directive.
+	directiveNone directiveKind = iota
+	// directiveFile is a directive applying to the whole file, such as
+	// //go:build or //lint:file-ignore, which never moves.
+	directiveFile
+	// directiveDecl is a directive applying to the declaration it is attached
+	// to, such as //go:noinline, //nolint or //go:generate, which moves along
+	// with the declaration.
+	directiveDecl
+)
+
+// fileDirectives are the prefixes of the directives applying to the whole file.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var fileDirectives = []string{"//go:build", "// +build", "//go:debug", "//lint:file-ignore"}
+
+// declDirectives are the prefixes of the directives applying to the declaration
+// they are attached to. Any other //go: directive, such as //go:noinline or
+// //go:linkname, applies to the declaration too.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var declDirectives = []string{"//go:", "//nolint", "//lint:ignore", "//export ", "//extern "}
+
+// ============================================================================
+//  Private Functions (ABC Order)
+// ============================================================================
+
+// classifyDirective returns the kind of directive of the comment text, such as
+// "//go:noinline", including the comment markers.
+func classifyDirective(text string) directiveKind {
+	for _, prefix := range fileDirectives {
+		if strings.HasPrefix(text, prefix) {
+			return directiveFile
+		}
+	}
+
+	for _, prefix := range declDirectives {
+		if strings.HasPrefix(text, prefix) {
+			return directiveDecl
+		}
+	}
+
+	return directiveNone
+}
+
+// declDirectivesStart returns the 0-based first line of the declaration
+// directives ending the comment group, such as //nolint:funlen below a license
+// block attached to a function, and whether there are any.
+func declDirectivesStart(group *ast.CommentGroup, fset *token.FileSet) (int, bool) {
+	start := -1
+
+	for i := len(group.List) - 1; i >= 0 && classifyDirective(group.List[i].Text) == directiveDecl; i-- {
+		start = fset.Position(group.List[i].Pos()).Line - 1 // Convert to 0-based
+	}
+
+	return start, start >= 0
+}
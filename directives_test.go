@@ -0,0 +1,70 @@
+package reorderfuncs
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+//	Private Functions (ABC Order)
+// ============================================================================
+
+func Test_classifyDirective(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		text   string
+		expect directiveKind
+	}{
+		{text: "//go:build linux", expect: directiveFile},
+		{text: "// +build linux", expect: directiveFile},
+		{text: "//go:debug panicnil=1", expect: directiveFile},
+		{text: "//lint:file-ignore U1000 generated code", expect: directiveFile},
+		{text: "//go:noinline", expect: directiveDecl},
+		{text: "//go:nosplit", expect: directiveDecl},
+		{text: "//go:linkname now time.now", expect: directiveDecl},
+		{text: "//go:generate stringer -type=Kind", expect: directiveDecl},
+		{text: "//go:embed testdata", expect: directiveDecl},
+		{text: "//nolint:funlen // table driven", expect: directiveDecl},
+		{text: "//nolint", expect: directiveDecl},
+		{text: "//lint:ignore SA1019 deprecated on purpose", expect: directiveDecl},
+		{text: "//export Add", expect: directiveDecl},
+		{text: "//extern open", expect: directiveDecl},
+		{text: "// go:noinline is not a directive with a space", expect: directiveNone},
+		{text: "// nolint", expect: directiveNone},
+		{text: "// TestFoo checks foo.", expect: directiveNone},
+		{text: "/* go:noinline */", expect: directiveNone},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expect, classifyDirective(test.text))
+		})
+	}
+}
+
+func Test_declDirectivesStart(t *testing.T) {
+	t.Parallel()
+
+	source := "package x\n\n// Copyright 2025 Foo\n//nolint:funlen\n//go:noinline\nfunc Foo() {}\n\n" +
+		"// Bar does bar.\nfunc Bar() {}\n"
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "x.go", source, parser.ParseComments)
+	require.NoError(t, err)
+
+	start, ok := declDirectivesStart(file.Comments[0], fset)
+
+	assert.True(t, ok)
+	assert.Equal(t, 3, start, "the directives start after the license")
+
+	_, ok = declDirectivesStart(file.Comments[1], fset)
+
+	assert.False(t, ok, "a doc comment without directives")
+}
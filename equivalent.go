@@ -0,0 +1,195 @@
+package reorderfuncs
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ErrNotEquivalent is returned with Options.CheckEquivalence when the reordered
+// output is not equivalent to the source, see Equivalent. Nothing is written
+// then.
+var ErrNotEquivalent = errors.New("reordered output is not equivalent to the source")
+
+// Difference describes a top-level declaration that differs between the two
+// sources compared by Equivalent.
+type Difference struct {
+	// Decl identifies the declaration: the function identity, such as "TestFoo"
+	// or "FooSuite.TestCreate", or the keyword and sorted names of a general
+	// declaration, such as "var a,b" or `import "testing"`.
+	Decl string
+	// Reason describes the difference, such as "declaration differs".
+	Reason string
+}
+
+// equivalenceDecl is the position-independent form of a top-level declaration.
+type equivalenceDecl struct {
+	// dump is the AST of the declaration, without its doc comment.
+	dump string
+	// doc is the text of the doc comment of the declaration.
+	doc string
+}
+
+// ============================================================================
+//  Public Functions (ABC Order)
+// ============================================================================
+
+// Equivalent reports whether the two Go sources hold the same top-level
+// declarations with the same doc comments, regardless of their order, their
+// positions, their formatting and the order of the specs that WithSortDecls
+// may sort. Unlike the line check of ReorderSource, it detects a doc comment
+// moved to the wrong declaration. The differences are sorted by declaration.
+// It returns an error if either source does not parse.
+func Equivalent(a, b []byte) (bool, []Difference, error) {
+	fileA, err := parser.ParseFile(token.NewFileSet(), "", a, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to parse the first source: %w", err)
+	}
+
+	fileB, err := parser.ParseFile(token.NewFileSet(), "", b, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to parse the second source: %w", err)
+	}
+
+	declsA, declsB := equivalenceDecls(fileA), equivalenceDecls(fileB)
+
+	keys := slices.AppendSeq(slices.Collect(maps.Keys(declsA)), maps.Keys(declsB))
+	slices.Sort(keys)
+
+	var differences []Difference
+
+	for _, key := range slices.Compact(keys) {
+		if reason := compareDecls(declsA[key], declsB[key]); reason != "" {
+			differences = append(differences, Difference{Decl: key, Reason: reason})
+		}
+	}
+
+	return len(differences) == 0, differences, nil
+}
+
+// ============================================================================
+//  Methods (ABC Order)
+// ============================================================================
+
+// String implements fmt.Stringer.
+func (d Difference) String() string {
+	return d.Decl + ": " + d.Reason
+}
+
+// ============================================================================
+//  Private Functions (ABC Order)
+// ============================================================================
+
+// commentText returns the text of the comment group including the comment
+// markers, so that directives are compared too, or "" if it is nil.
+func commentText(group *ast.CommentGroup) string {
+	if group == nil {
+		return ""
+	}
+
+	texts := make([]string, 0, len(group.List))
+	for _, comment := range group.List {
+		texts = append(texts, comment.Text)
+	}
+
+	return strings.Join(texts, "\n")
+}
+
+// compareDecls compares the sorted declarations of both sources sharing an
+// identity and returns the reason why they differ, or "" if they do not.
+func compareDecls(a, b []equivalenceDecl) string {
+	switch {
+	case len(a) == 0:
+		return "missing in the first source"
+	case len(b) == 0:
+		return "missing in the second source"
+	case len(a) != len(b):
+		return fmt.Sprintf("declared %d times instead of %d", len(b), len(a))
+	}
+
+	for i := range a {
+		if a[i].dump != b[i].dump {
+			return "declaration differs"
+		}
+	}
+
+	for i := range a {
+		if a[i].doc != b[i].doc {
+			return "doc comment differs"
+		}
+	}
+
+	return ""
+}
+
+// dumpNode returns the AST of the node, without the positions and the resolved
+// objects, which differ between equivalent sources.
+func dumpNode(node ast.Node) string {
+	var dump strings.Builder
+
+	filter := func(name string, value reflect.Value) bool {
+		return name != "Obj" && name != "Scope" && value.Type() != reflect.TypeFor[token.Pos]()
+	}
+
+	_ = ast.Fprint(&dump, nil, node, filter) // Writing to a strings.Builder never fails
+
+	return dump.String()
+}
+
+// equivalenceDecls maps the identity of the top-level declarations of the file
+// to their position-independent forms, sorted so that the declarations sharing
+// an identity, such as init, are compared in any order.
+func equivalenceDecls(file *ast.File) map[string][]equivalenceDecl {
+	decls := make(map[string][]equivalenceDecl, len(file.Decls))
+
+	for _, decl := range file.Decls {
+		var identity, doc string
+
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			identity, doc, decl.Doc = funcIdentity(decl), commentText(decl.Doc), nil
+		case *ast.GenDecl:
+			identity, doc, decl.Doc = genDeclNames(decl), commentText(decl.Doc), nil
+
+			if isSortableBlock(decl) { // SortDecls may reorder the specs
+				slices.SortFunc(decl.Specs, func(a, b ast.Spec) int {
+					return strings.Compare(dumpNode(a), dumpNode(b))
+				})
+			}
+		default:
+			continue
+		}
+
+		decls[identity] = append(decls[identity], equivalenceDecl{dump: dumpNode(decl), doc: doc})
+	}
+
+	for _, equivalents := range decls {
+		slices.SortFunc(equivalents, func(a, b equivalenceDecl) int {
+			return cmp.Or(strings.Compare(a.dump, b.dump), strings.Compare(a.doc, b.doc))
+		})
+	}
+
+	return decls
+}
+
+// verifyEquivalence returns an error wrapping ErrNotEquivalent that describes
+// the first difference if the output is not equivalent to the source.
+func verifyEquivalence(filename string, src, output []byte) error {
+	equivalent, differences, err := Equivalent(src, output)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrNotEquivalent, filename, err)
+	}
+
+	if !equivalent {
+		return fmt.Errorf("%w: %s: %s", ErrNotEquivalent, filename, differences[0])
+	}
+
+	return nil
+}
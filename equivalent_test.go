@@ -0,0 +1,125 @@
+package reorderfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+//	Public Functions (ABC Order)
+// ============================================================================
+
+func TestEquivalent(t *testing.T) {
+	t.Parallel()
+
+	src := "package main\n\nimport \"testing\"\n\nvar (\n\tb = 1\n\ta = 2\n)\n\n" +
+		"func init() { println(1) }\n\nfunc init() {}\n\n// TestB doc.\n//\n//nolint:funlen\n" +
+		"func TestB(t *testing.T) {\n\tt.Log(\"b\") // logs b\n}\n\n// TestA doc.\nfunc TestA(t *testing.T) {}\n"
+
+	tests := []struct {
+		name   string
+		output string
+		expect []Difference
+	}{
+		{
+			name: "reordered and reformatted",
+			output: "package main\n\nimport \"testing\"\n\nvar (\n\ta = 2\n\tb = 1\n)\n\nfunc init() {}\n\n" +
+				"func init() {\n\tprintln(1)\n}\n\n// TestA doc.\nfunc TestA(t *testing.T) {}\n\n// TestB doc.\n//\n" +
+				"//nolint:funlen\nfunc TestB(t *testing.T) {\n\n\tt.Log(\"b\")\n}\n",
+		},
+		{
+			name: "doc comment moved to the wrong function",
+			output: "package main\n\nimport \"testing\"\n\nvar (\n\tb = 1\n\ta = 2\n)\n\n" +
+				"func init() { println(1) }\n\nfunc init() {}\n\n// TestA doc.\n// TestB doc.\n//\n//nolint:funlen\n" +
+				"func TestB(t *testing.T) {\n\tt.Log(\"b\") // logs b\n}\n\nfunc TestA(t *testing.T) {}\n",
+			expect: []Difference{
+				{Decl: "TestA", Reason: "doc comment differs"},
+				{Decl: "TestB", Reason: "doc comment differs"},
+			},
+		},
+		{
+			name: "directive detached",
+			output: "package main\n\nimport \"testing\"\n\nvar (\n\tb = 1\n\ta = 2\n)\n\n" +
+				"func init() { println(1) }\n\nfunc init() {}\n\n//nolint:funlen\n\n// TestB doc.\n" +
+				"func TestB(t *testing.T) {\n\tt.Log(\"b\") // logs b\n}\n\n// TestA doc.\nfunc TestA(t *testing.T) {}\n",
+			expect: []Difference{{Decl: "TestB", Reason: "doc comment differs"}},
+		},
+		{
+			name: "changed declarations",
+			output: "package main\n\nimport \"testing\"\n\nvar (\n\tb = 1\n\ta = 3\n)\n\n" +
+				"func init() { println(2) }\n\n// TestB doc.\n//\n//nolint:funlen\n" +
+				"func TestB(t *testing.T) {\n\tt.Log(\"b\") // logs b\n}\n\n// TestA doc.\nfunc TestA(t *testing.T) {}\n\n" +
+				"// TestC doc.\nfunc TestC(t *testing.T) {}\n",
+			expect: []Difference{
+				{Decl: "TestC", Reason: "missing in the first source"},
+				{Decl: "init", Reason: "declared 1 times instead of 2"},
+				{Decl: "var a,b", Reason: "declaration differs"},
+			},
+		},
+		{
+			name:   "dropped declarations",
+			output: "package main\n\nfunc init() { println(1) }\n\nfunc init() {}\n",
+			expect: []Difference{
+				{Decl: "TestA", Reason: "missing in the second source"},
+				{Decl: "TestB", Reason: "missing in the second source"},
+				{Decl: `import "testing"`, Reason: "missing in the second source"},
+				{Decl: "var a,b", Reason: "missing in the second source"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			equivalent, differences, err := Equivalent([]byte(src), []byte(test.output))
+
+			require.NoError(t, err)
+			assert.Equal(t, len(test.expect) == 0, equivalent)
+			assert.Equal(t, test.expect, differences)
+		})
+	}
+}
+
+func TestEquivalent_parse_error(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := Equivalent([]byte("package main\n\nfunc TestA() {\n"), []byte("package main\n"))
+	require.ErrorContains(t, err, "failed to parse the first source")
+
+	_, _, err = Equivalent([]byte("package main\n"), []byte("func TestA() {}\n"))
+	require.ErrorContains(t, err, "failed to parse the second source")
+}
+
+// ============================================================================
+//	Methods (ABC Order)
+// ============================================================================
+
+func TestDifference_String(t *testing.T) {
+	t.Parallel()
+
+	difference := Difference{Decl: "FooSuite.TestCreate", Reason: "doc comment differs"}
+
+	assert.Equal(t, "FooSuite.TestCreate: doc comment differs", difference.String())
+}
+
+// ============================================================================
+//	Private Functions (ABC Order)
+// ============================================================================
+
+func Test_verifyEquivalence(t *testing.T) {
+	t.Parallel()
+
+	src := []byte("package main\n\n// TestB doc.\nfunc TestB() {}\n\nfunc TestA() {}\n")
+	reordered := []byte("package main\n\nfunc TestA() {}\n\n// TestB doc.\nfunc TestB() {}\n")
+	misplaced := []byte("package main\n\n// TestB doc.\nfunc TestA() {}\n\nfunc TestB() {}\n")
+
+	require.NoError(t, verifyEquivalence("foo_test.go", src, reordered))
+
+	err := verifyEquivalence("foo_test.go", src, misplaced)
+
+	require.ErrorIs(t, err, ErrNotEquivalent)
+	require.ErrorContains(t, err, "foo_test.go: TestA: doc comment differs")
+}
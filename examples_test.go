@@ -11,6 +11,7 @@ import (
 	"go/token"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	reorderfuncs "github.com/KEINOS/go-ReOrderFuncs"
@@ -181,3 +182,697 @@ func Test_bob(t *testing.T) {
 	// - Test_bob
 	// Non-test lines: 7
 }
+
+func ExampleReorder() {
+	src := strings.NewReader(`package main
+
+import "testing"
+
+func Test_bob(t *testing.T) {}
+
+func Test_alice(t *testing.T) {}
+`)
+
+	// Reorder from an io.Reader into an io.Writer
+	err := reorderfuncs.Reorder("example_test.go", src, os.Stdout)
+	if err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// package main
+	//
+	// import "testing"
+	//
+	// func Test_alice(t *testing.T) {}
+	//
+	// func Test_bob(t *testing.T) {}
+}
+
+func ExampleReorderSource() {
+	src := []byte(`package main
+
+import "testing"
+
+func Test_charlie(t *testing.T) {}
+
+func Test_alice(t *testing.T) {}
+
+func Test_bob(t *testing.T) {}
+`)
+
+	// Reorder the in-memory source without touching the filesystem
+	output, err := reorderfuncs.ReorderSource("example_test.go", src)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(string(output))
+
+	// Output:
+	// package main
+	//
+	// import "testing"
+	//
+	// func Test_alice(t *testing.T) {}
+	//
+	// func Test_bob(t *testing.T) {}
+	//
+	// func Test_charlie(t *testing.T) {}
+}
+
+func ExampleEquivalent() {
+	src := []byte(`package main
+
+// TestB checks B.
+func TestB(t *testing.T) {}
+
+// TestA checks A.
+func TestA(t *testing.T) {}
+`)
+
+	// The doc comments of the functions were swapped
+	output := []byte(`package main
+
+// TestB checks B.
+func TestA(t *testing.T) {}
+
+// TestA checks A.
+func TestB(t *testing.T) {}
+`)
+
+	equivalent, differences, err := reorderfuncs.Equivalent(src, output)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(equivalent)
+
+	for _, difference := range differences {
+		fmt.Println(difference)
+	}
+
+	// Output:
+	// false
+	// TestA: doc comment differs
+	// TestB: doc comment differs
+}
+
+func ExampleWithCompare() {
+	src := []byte(`package main
+
+import "testing"
+
+func Test_alice(t *testing.T) {}
+
+func Test_bob(t *testing.T) {}
+`)
+
+	// Sort the test functions in reverse order
+	reverse := func(a, b string) int { return strings.Compare(b, a) }
+
+	output, err := reorderfuncs.ReorderSource("example_test.go", src, reorderfuncs.WithCompare(reverse))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(string(output))
+
+	// Output:
+	// package main
+	//
+	// import "testing"
+	//
+	// func Test_bob(t *testing.T) {}
+	//
+	// func Test_alice(t *testing.T) {}
+}
+
+func ExampleCompareNatural() {
+	names := []string{"Test_case10", "Test_case2", "Test_case1"}
+
+	slices.SortFunc(names, reorderfuncs.CompareNatural)
+
+	fmt.Println(names)
+
+	// Output: [Test_case1 Test_case2 Test_case10]
+}
+
+func ExampleWithNaturalSort() {
+	src := []byte(`package main
+
+import "testing"
+
+func Test_case10(t *testing.T) {}
+
+func Test_case2(t *testing.T) {}
+`)
+
+	output, err := reorderfuncs.ReorderSource("example_test.go", src, reorderfuncs.WithNaturalSort())
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(string(output))
+
+	// Output:
+	// package main
+	//
+	// import "testing"
+	//
+	// func Test_case2(t *testing.T) {}
+	//
+	// func Test_case10(t *testing.T) {}
+}
+
+func ExampleWithSortKey() {
+	src := []byte(`package main
+
+import "testing"
+
+func TestZeta(t *testing.T) {}
+
+func Test_alpha(t *testing.T) {}
+
+func TestBeta(t *testing.T) {}
+`)
+
+	// Ignore the case and the "Test"/"Test_" prefix when sorting
+	key := reorderfuncs.SortKeyFoldCase | reorderfuncs.SortKeyTrimPrefix
+
+	output, err := reorderfuncs.ReorderSource("example_test.go", src, reorderfuncs.WithSortKey(key))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(string(output))
+
+	// Output:
+	// package main
+	//
+	// import "testing"
+	//
+	// func Test_alpha(t *testing.T) {}
+	//
+	// func TestBeta(t *testing.T) {}
+	//
+	// func TestZeta(t *testing.T) {}
+}
+
+func ExampleWithPlacement() {
+	src := []byte(`package main
+
+import "testing"
+
+func Test_bob(t *testing.T) {}
+
+func helper() {}
+
+func Test_alice(t *testing.T) {}
+`)
+
+	// Sort the tests among their own slots, keeping helper() where it is
+	output, err := reorderfuncs.ReorderSource("example_test.go", src,
+		reorderfuncs.WithPlacement(reorderfuncs.PlaceInPlace))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(string(output))
+
+	// Output:
+	// package main
+	//
+	// import "testing"
+	//
+	// func Test_alice(t *testing.T) {}
+	//
+	// func helper() {}
+	//
+	// func Test_bob(t *testing.T) {}
+}
+
+func ExampleWithMarker() {
+	src := []byte(`package main
+
+import "testing"
+
+func helper() {}
+
+// Tests
+
+func Test_bob(t *testing.T) {}
+
+func Test_alice(t *testing.T) {}
+
+func anotherHelper() {}
+`)
+
+	// Keep the tests right after the "// Tests" anchor comment
+	output, err := reorderfuncs.ReorderSource("example_test.go", src, reorderfuncs.WithMarker("// Tests"))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(string(output))
+
+	// Output:
+	// package main
+	//
+	// import "testing"
+	//
+	// func helper() {}
+	//
+	// // Tests
+	//
+	// func Test_alice(t *testing.T) {}
+	//
+	// func Test_bob(t *testing.T) {}
+	//
+	// func anotherHelper() {}
+}
+
+func ExampleWithKinds() {
+	src := []byte(`package main
+
+import "testing"
+
+func ExampleHello() {}
+
+func BenchmarkHello(b *testing.B) {}
+
+func TestHello(t *testing.T) {}
+
+func FuzzHello(f *testing.F) {}
+`)
+
+	// Tests first, then benchmarks, fuzz tests and examples
+	output, err := reorderfuncs.ReorderSource("example_test.go", src,
+		reorderfuncs.WithKinds(reorderfuncs.AllKinds()...))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(string(output))
+
+	// Output:
+	// package main
+	//
+	// import "testing"
+	//
+	// func TestHello(t *testing.T) {}
+	//
+	// func BenchmarkHello(b *testing.B) {}
+	//
+	// func FuzzHello(f *testing.F) {}
+	//
+	// func ExampleHello() {}
+}
+
+func ExampleWithSpacing() {
+	src := []byte(`package main
+import "testing"
+
+
+
+func helper() {}
+func TestBob(t *testing.T) {}
+
+
+func TestAlice(t *testing.T) {}
+`)
+
+	// Exactly one blank line between every declaration
+	output, err := reorderfuncs.ReorderSource("spacing_test.go", src,
+		reorderfuncs.WithSpacing(reorderfuncs.SpacingNormalize))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(string(output))
+
+	// Output:
+	// package main
+	//
+	// import "testing"
+	//
+	// func helper() {}
+	//
+	// func TestAlice(t *testing.T) {}
+	//
+	// func TestBob(t *testing.T) {}
+}
+
+func ExampleWithStrict() {
+	src := []byte(`package main
+
+import "testing"
+
+func TestZulu(t *testing.T) {}
+
+func Testify() {}
+
+func TestAlpha(t *testing.T) {}
+`)
+
+	// Testify is not a test per go test rules, so it stays where it is
+	output, err := reorderfuncs.ReorderSource("example_test.go", src, reorderfuncs.WithStrict())
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(string(output))
+
+	// Output:
+	// package main
+	//
+	// import "testing"
+	//
+	// func Testify() {}
+	//
+	// func TestAlpha(t *testing.T) {}
+	//
+	// func TestZulu(t *testing.T) {}
+}
+
+func ExampleWithCommented() {
+	src := []byte(`package main
+
+import "testing"
+
+func TestCharlie(t *testing.T) {}
+
+// func TestBob(t *testing.T) {
+// 	t.Skip()
+// }
+
+func TestAlice(t *testing.T) {}
+`)
+
+	// The commented-out TestBob is sorted by its own name
+	output, err := reorderfuncs.ReorderSource("commented_test.go", src,
+		reorderfuncs.WithCommented(reorderfuncs.CommentedSort))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(string(output))
+
+	// Output:
+	// package main
+	//
+	// import "testing"
+	//
+	// func TestAlice(t *testing.T) {}
+	//
+	// // func TestBob(t *testing.T) {
+	// // 	t.Skip()
+	// // }
+	//
+	// func TestCharlie(t *testing.T) {}
+}
+
+func ExampleWithFloating() {
+	src := []byte(`package main
+
+import "testing"
+
+func TestBob(t *testing.T) {}
+
+// TODO: test the error cases.
+
+// TestAlice checks Alice.
+func TestAlice(t *testing.T) {}
+`)
+
+	// Only the doc comment moves, the TODO note stays where it was
+	output, err := reorderfuncs.ReorderSource("floating_test.go", src,
+		reorderfuncs.WithFloating(reorderfuncs.FloatingKeep))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(string(output))
+
+	// Output:
+	// package main
+	//
+	// import "testing"
+	//
+	// // TODO: test the error cases.
+	//
+	// // TestAlice checks Alice.
+	// func TestAlice(t *testing.T) {}
+	//
+	// func TestBob(t *testing.T) {}
+}
+
+func ExampleWithGofmt() {
+	src := []byte(`package main
+
+import "testing"
+
+func TestBob(t *testing.T)  {
+  t.Log("bob")
+}
+
+func TestAlice(t *testing.T){}
+`)
+
+	// The output is gofmt-clean even if the source is not
+	output, err := reorderfuncs.ReorderSource("gofmt_test.go", src, reorderfuncs.WithGofmt())
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(string(output))
+
+	// Output:
+	// package main
+	//
+	// import "testing"
+	//
+	// func TestAlice(t *testing.T) {}
+	//
+	// func TestBob(t *testing.T) {
+	// 	t.Log("bob")
+	// }
+}
+
+func ExampleWithGroupSuites() {
+	src := []byte(`package main
+
+import "testing"
+
+func (s *FooSuite) TestDelete() {}
+
+func TestZulu(t *testing.T) {}
+
+func (s *FooSuite) TestCreate() {}
+
+func TestFooSuite(t *testing.T) {}
+
+func TestAlpha(t *testing.T) {}
+`)
+
+	// Suite methods follow the TestFooSuite runner
+	output, err := reorderfuncs.ReorderSource("example_test.go", src, reorderfuncs.WithGroupSuites())
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(string(output))
+
+	// Output:
+	// package main
+	//
+	// import "testing"
+	//
+	// func TestAlpha(t *testing.T) {}
+	//
+	// func TestFooSuite(t *testing.T) {}
+	//
+	// func (s *FooSuite) TestCreate() {}
+	//
+	// func (s *FooSuite) TestDelete() {}
+	//
+	// func TestZulu(t *testing.T) {}
+}
+
+func ExampleWithTestMain() {
+	src := []byte(`package main
+
+import "testing"
+
+func TestParse(t *testing.T) {}
+
+func TestMain(m *testing.M) {}
+
+func TestLoad(t *testing.T) {}
+`)
+
+	// TestMain always comes first in the test block
+	output, err := reorderfuncs.ReorderSource("example_test.go", src,
+		reorderfuncs.WithTestMain(reorderfuncs.TestMainFirst))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(string(output))
+
+	// Output:
+	// package main
+	//
+	// import "testing"
+	//
+	// func TestMain(m *testing.M) {}
+	//
+	// func TestLoad(t *testing.T) {}
+	//
+	// func TestParse(t *testing.T) {}
+}
+
+func ExampleWithMode() {
+	src := []byte(`package shapes
+
+func scale(v float64) float64 { return v * 2 }
+
+// Area returns the area.
+func Area(r float64) float64 { return scale(r) }
+
+func clamp(v float64) float64 { return v }
+`)
+
+	// Order every function, exported ones first
+	output, err := reorderfuncs.ReorderSource("shapes.go", src,
+		reorderfuncs.WithMode(reorderfuncs.ModeFuncs))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(string(output))
+
+	// Output:
+	// package shapes
+	//
+	// // Area returns the area.
+	// func Area(r float64) float64 { return scale(r) }
+	//
+	// func clamp(v float64) float64 { return v }
+	//
+	// func scale(v float64) float64 { return v * 2 }
+}
+
+func ExampleWithBanners() {
+	src := []byte(`package main
+
+import "testing"
+
+func BenchmarkHello(b *testing.B) {}
+
+func TestHello(t *testing.T) {}
+`)
+
+	// Separate the tests from the benchmarks with section banners
+	output, err := reorderfuncs.ReorderSource("example_test.go", src,
+		reorderfuncs.WithKinds(reorderfuncs.KindTest, reorderfuncs.KindBenchmark),
+		reorderfuncs.WithBanners("// --- %s ---"))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(string(output))
+
+	// Output:
+	// package main
+	//
+	// import "testing"
+	//
+	// // --- Tests ---
+	//
+	// func TestHello(t *testing.T) {}
+	//
+	// // --- Benchmarks ---
+	//
+	// func BenchmarkHello(b *testing.B) {}
+}
+
+func ExampleWithMode_types() {
+	src := []byte(`package store
+
+func (s *Store) Get(key string) string { return s.items[key] }
+
+func Open() *Store { return NewStore() }
+
+// Store holds the items.
+type Store struct{ items map[string]string }
+
+func NewStore() *Store { return &Store{} }
+`)
+
+	// Each type is followed by its constructors and methods
+	output, err := reorderfuncs.ReorderSource("store.go", src,
+		reorderfuncs.WithMode(reorderfuncs.ModeTypes))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(string(output))
+
+	// Output:
+	// package store
+	//
+	// // Store holds the items.
+	// type Store struct{ items map[string]string }
+	//
+	// func NewStore() *Store { return &Store{} }
+	//
+	// func (s *Store) Get(key string) string { return s.items[key] }
+	//
+	// func Open() *Store { return NewStore() }
+}
+
+func ExampleWithSortDecls() {
+	src := []byte(`package config
+
+func Load() {}
+
+var (
+	timeout = 5
+	retries = 3
+)
+
+const (
+	LevelDebug = iota
+	LevelInfo
+)
+
+type Config struct{}
+`)
+
+	// Types, constants and variables first, iota blocks untouched
+	output, err := reorderfuncs.ReorderSource("config.go", src,
+		reorderfuncs.WithMode(reorderfuncs.ModeFuncs), reorderfuncs.WithSortDecls())
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(string(output))
+
+	// Output:
+	// package config
+	//
+	// type Config struct{}
+	//
+	// const (
+	// 	LevelDebug = iota
+	// 	LevelInfo
+	// )
+	//
+	// var (
+	// 	retries = 3
+	// 	timeout = 5
+	// )
+	//
+	// func Load() {}
+}
@@ -0,0 +1,43 @@
+package reorderfuncs
+
+import (
+	"fmt"
+	"go/format"
+)
+
+// FormatError is returned when the reordered output cannot be formatted with
+// Options.Gofmt. It wraps the error of go/format.
+type FormatError struct {
+	// Filename is the name of the source, as given to ReorderSource.
+	Filename string
+	// Err is the error returned by format.Source.
+	Err error
+}
+
+// ============================================================================
+//  Methods (ABC Order)
+// ============================================================================
+
+// Error implements the error interface.
+func (e *FormatError) Error() string {
+	return fmt.Sprintf("failed to format output of %s: %v", e.Filename, e.Err)
+}
+
+// Unwrap returns the error of go/format.
+func (e *FormatError) Unwrap() error {
+	return e.Err
+}
+
+// ============================================================================
+//  Private Functions (ABC Order)
+// ============================================================================
+
+// formatOutput formats the reordered output like gofmt does.
+func formatOutput(filename string, output []byte) ([]byte, error) {
+	formatted, err := format.Source(output)
+	if err != nil {
+		return nil, &FormatError{Filename: filename, Err: err}
+	}
+
+	return formatted, nil
+}
@@ -0,0 +1,46 @@
+package reorderfuncs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+//	Methods (ABC Order)
+// ============================================================================
+
+func TestFormatError(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("expected declaration") //nolint:err113 // allow dynamic error for test
+	err := error(&FormatError{Filename: "foo_test.go", Err: cause})
+
+	var formatErr *FormatError
+
+	require.ErrorAs(t, err, &formatErr)
+	require.ErrorIs(t, err, cause)
+	assert.Equal(t, "failed to format output of foo_test.go: expected declaration", err.Error())
+}
+
+// ============================================================================
+//	Private Functions (ABC Order)
+// ============================================================================
+
+func Test_formatOutput(t *testing.T) {
+	t.Parallel()
+
+	formatted, err := formatOutput("foo.go", []byte("package foo\n\n\n\nfunc  Foo( ){}\n"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "package foo\n\nfunc Foo() {}\n", string(formatted))
+
+	_, err = formatOutput("foo.go", []byte("package foo\n\nfunc {\n"))
+
+	var formatErr *FormatError
+
+	require.ErrorAs(t, err, &formatErr)
+	assert.Equal(t, "foo.go", formatErr.Filename)
+}
@@ -0,0 +1,354 @@
+package reorderfuncs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kind is the kind of a function recognized by `go test`.
+type Kind int
+
+const (
+	// KindTest is a test function, such as TestXxx(t *testing.T).
+	KindTest Kind = iota
+	// KindBenchmark is a benchmark function, such as BenchmarkXxx(b *testing.B).
+	KindBenchmark
+	// KindFuzz is a fuzz test function, such as FuzzXxx(f *testing.F).
+	KindFuzz
+	// KindExample is an example function, such as ExampleXxx().
+	KindExample
+	// KindHelper is any other function, such as a test helper. It matches
+	// every function that is not of the other kinds, methods excepted in
+	// Strict mode.
+	KindHelper
+)
+
+// Kinds is an ordered list of function kinds. It implements flag.Value as a
+// comma-separated list of kind names, e.g. "test,benchmark,fuzz,example".
+type Kinds []Kind
+
+// kindNames holds the command-line names, the name prefixes, the parameter
+// types and the banner titles of the kinds. The helper kind, with no prefix,
+// must be the last one.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var kindNames = []struct {
+	kind   Kind
+	name   string
+	prefix string
+	param  string // type name in the testing package of the single parameter
+	title  string
+}{
+	{kind: KindTest, name: "test", prefix: testFuncPrefix, param: "T", title: "Tests"},
+	{kind: KindBenchmark, name: "benchmark", prefix: "Benchmark", param: "B", title: "Benchmarks"},
+	{kind: KindFuzz, name: "fuzz", prefix: "Fuzz", param: "F", title: "Fuzz Tests"},
+	{kind: KindExample, name: "example", prefix: "Example", param: "", title: "Examples"},
+	{kind: KindHelper, name: "helper", prefix: "", param: "", title: "Helpers"},
+}
+
+const (
+	// testMainName is the name of the function that controls the test binary.
+	testMainName = "TestMain"
+	// testingImportPath is the import path of the standard testing package.
+	testingImportPath = "testing"
+)
+
+// ============================================================================
+//  Public Functions (ABC Order)
+// ============================================================================
+
+// AllKinds returns all the function kinds in the conventional order: tests,
+// benchmarks, fuzz tests and then examples.
+func AllKinds() Kinds {
+	return Kinds{KindTest, KindBenchmark, KindFuzz, KindExample}
+}
+
+// ============================================================================
+//  Methods (ABC Order)
+// ============================================================================
+
+// Prefix returns the function name prefix of the kind, such as "Benchmark".
+func (k Kind) Prefix() string {
+	for _, entry := range kindNames {
+		if entry.kind == k {
+			return entry.prefix
+		}
+	}
+
+	return ""
+}
+
+// String implements fmt.Stringer.
+func (k Kind) String() string {
+	for _, entry := range kindNames {
+		if entry.kind == k {
+			return entry.name
+		}
+	}
+
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Set implements flag.Value. It parses a comma-separated list of kind names.
+func (k *Kinds) Set(value string) error {
+	var kinds Kinds
+
+	for name := range strings.SplitSeq(value, ",") {
+		kind, err := parseKindName(strings.TrimSpace(name))
+		if err != nil {
+			return err
+		}
+
+		kinds = append(kinds, kind)
+	}
+
+	*k = kinds
+
+	return nil
+}
+
+// String implements fmt.Stringer and flag.Value.
+func (k Kinds) String() string {
+	names := make([]string, len(k))
+	for i, kind := range k {
+		names[i] = kind.String()
+	}
+
+	return strings.Join(names, ",")
+}
+
+// ============================================================================
+//  Private Functions (ABC Order)
+// ============================================================================
+
+// buildStrictKinds maps the identity of the test functions to their kind
+// following the `go test` rules in Strict mode, so that a helper such as
+// Testify() is not grouped with the tests. It returns an empty map otherwise.
+func buildStrictKinds(
+	file *ast.File,
+	fset *token.FileSet,
+	testFuncPos map[string][2]int,
+	options Options,
+) map[string]Kind {
+	kinds := make(map[string]Kind)
+	if !options.Strict || options.Match != nil || options.Mode == ModeFuncs {
+		return kinds
+	}
+
+	testingName := testingImportName(file)
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+
+		if _, ok := testFuncPos[funcKey(fn, fset)]; ok {
+			kinds[funcKey(fn, fset)] = options.kinds().kindOfStrict(fn, testingName)
+		}
+	}
+
+	return kinds
+}
+
+// hasKindName reports whether the name has the given prefix followed by nothing
+// or by a non-lowercase rune, as required by `go test`. For example "Test" and
+// "TestFoo" have the "Test" prefix but "Testify" does not.
+func hasKindName(name, prefix string) bool {
+	rest, ok := strings.CutPrefix(name, prefix)
+	if !ok {
+		return false
+	}
+
+	if rest == "" {
+		return true
+	}
+
+	r, _ := utf8.DecodeRuneInString(rest)
+
+	return !unicode.IsLower(r)
+}
+
+// isTestingPointer reports whether the type expression is a pointer to the
+// named type of the testing package, imported as testingName.
+func isTestingPointer(expr ast.Expr, testingName, typeName string) bool {
+	star, ok := expr.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+
+	switch typ := star.X.(type) {
+	case *ast.SelectorExpr:
+		pkg, ok := typ.X.(*ast.Ident)
+
+		return ok && pkg.Name == testingName && typ.Sel.Name == typeName
+	case *ast.Ident:
+		return testingName == "." && typ.Name == typeName // dot import
+	default:
+		return false
+	}
+}
+
+// kindOf returns the kind of a function from its name prefix. Names without a
+// known prefix are reported as KindHelper.
+func kindOf(name string) Kind {
+	for _, entry := range kindNames {
+		if strings.HasPrefix(name, entry.prefix) {
+			return entry.kind
+		}
+	}
+
+	return KindHelper
+}
+
+// kindOfStrict returns the first of the kinds the function matches following
+// the `go test` rules, or the kind of its name if it matches none.
+func (k Kinds) kindOfStrict(fn *ast.FuncDecl, testingName string) Kind {
+	for _, kind := range k {
+		if kind.matchStrict(fn, testingName) {
+			return kind
+		}
+	}
+
+	return kindOf(fn.Name.Name)
+}
+
+// match reports whether the name starts with the prefix of any of the kinds.
+func (k Kinds) match(name string) bool {
+	for _, kind := range k {
+		if strings.HasPrefix(name, kind.Prefix()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchStrict reports whether the function is a valid function of the kind
+// following the `go test` rules: the name has the kind prefix followed by a
+// non-lowercase rune, there is no receiver, no type parameters, no results and
+// a single *testing.T, *testing.B or *testing.F parameter (none for examples).
+// TestMain(m *testing.M) is recognized as a test. Helpers are the functions
+// that are not of any other kind.
+func (k Kind) matchStrict(fn *ast.FuncDecl, testingName string) bool {
+	if k == KindHelper {
+		return fn.Recv == nil && !AllKinds().matchStrict(fn, testingName)
+	}
+
+	if fn.Recv != nil || fn.Type.TypeParams != nil || !hasKindName(fn.Name.Name, k.Prefix()) {
+		return false
+	}
+
+	if fn.Type.Results != nil && len(fn.Type.Results.List) > 0 {
+		return false
+	}
+
+	params := fn.Type.Params.List
+	if k == KindExample {
+		return len(params) == 0
+	}
+
+	if len(params) != 1 || len(params[0].Names) > 1 {
+		return false
+	}
+
+	if k == KindTest && fn.Name.Name == testMainName && isTestingPointer(params[0].Type, testingName, "M") {
+		return true
+	}
+
+	return isTestingPointer(params[0].Type, testingName, k.param())
+}
+
+// matchStrict reports whether the function is a valid function of any of the
+// kinds following the `go test` rules.
+func (k Kinds) matchStrict(fn *ast.FuncDecl, testingName string) bool {
+	for _, kind := range k {
+		if kind.matchStrict(fn, testingName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// param returns the type name of the single parameter of the kind in the
+// testing package, such as "T" for tests.
+func (k Kind) param() string {
+	for _, entry := range kindNames {
+		if entry.kind == k {
+			return entry.param
+		}
+	}
+
+	return ""
+}
+
+// parseKindName returns the kind of the given command-line name.
+func parseKindName(name string) (Kind, error) {
+	for _, entry := range kindNames {
+		if entry.name == name {
+			return entry.kind, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: unknown kind %q", ErrInvalidOption, name)
+}
+
+// rank returns the position of the kind in the list. Kinds not in the list are
+// ranked after all the listed ones.
+func (k Kinds) rank(kind Kind) int {
+	for i, listed := range k {
+		if listed == kind {
+			return i
+		}
+	}
+
+	return len(k)
+}
+
+// testingImportName returns the name the testing package is imported as in the
+// file, such as "testing", an alias or "." for dot imports. It returns an empty
+// string if the testing package is not imported.
+func testingImportName(file *ast.File) string {
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || path != testingImportPath {
+			continue
+		}
+
+		if spec.Name != nil {
+			return spec.Name.Name
+		}
+
+		return testingImportPath
+	}
+
+	return ""
+}
+
+// title returns the banner title of the group of functions of the kind.
+func (k Kind) title() string {
+	for _, entry := range kindNames {
+		if entry.kind == k {
+			return entry.title
+		}
+	}
+
+	return k.String()
+}
+
+// trimKindPrefix removes the kind prefix, and the "_" following it, from the name.
+func trimKindPrefix(name string) string {
+	for _, entry := range kindNames {
+		if trimmed, ok := strings.CutPrefix(name, entry.prefix); ok {
+			return strings.TrimPrefix(trimmed, "_")
+		}
+	}
+
+	return name
+}
@@ -0,0 +1,253 @@
+package reorderfuncs
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+//	Public Functions (ABC Order)
+// ============================================================================
+
+func TestAllKinds(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, Kinds{KindTest, KindBenchmark, KindFuzz, KindExample}, AllKinds())
+	assert.Equal(t, "test,benchmark,fuzz,example", AllKinds().String())
+}
+
+func TestKind_Prefix(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "Test", KindTest.Prefix())
+	assert.Equal(t, "Benchmark", KindBenchmark.Prefix())
+	assert.Equal(t, "Fuzz", KindFuzz.Prefix())
+	assert.Equal(t, "Example", KindExample.Prefix())
+	assert.Empty(t, KindHelper.Prefix())
+	assert.Empty(t, Kind(-1).Prefix())
+}
+
+func TestKind_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "benchmark", KindBenchmark.String())
+	assert.Equal(t, "Kind(-1)", Kind(-1).String())
+}
+
+func TestKinds_Set(t *testing.T) {
+	t.Parallel()
+
+	var kinds Kinds
+
+	require.NoError(t, kinds.Set("example, test"))
+	assert.Equal(t, Kinds{KindExample, KindTest}, kinds)
+
+	err := kinds.Set("test,unknown")
+
+	require.ErrorIs(t, err, ErrInvalidOption)
+	assert.Contains(t, err.Error(), `unknown kind "unknown"`)
+	assert.Equal(t, Kinds{KindExample, KindTest}, kinds, "kinds should be unchanged on error")
+}
+
+// ============================================================================
+//	Private Functions (ABC Order)
+// ============================================================================
+
+func Test_Kind_matchStrict(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		decl   string
+		expect bool
+	}{
+		{name: "test", decl: "func TestFoo(t *testing.T) {}", expect: true},
+		{name: "test with underscore", decl: "func Test_foo(t *testing.T) {}", expect: true},
+		{name: "bare Test", decl: "func Test(t *testing.T) {}", expect: true},
+		{name: "unnamed parameter", decl: "func TestFoo(*testing.T) {}", expect: true},
+		{name: "TestMain", decl: "func TestMain(m *testing.M) {}", expect: true},
+		{name: "benchmark", decl: "func BenchmarkFoo(b *testing.B) {}", expect: true},
+		{name: "fuzz", decl: "func FuzzFoo(f *testing.F) {}", expect: true},
+		{name: "example", decl: "func ExampleFoo() {}", expect: true},
+		{name: "lowercase after prefix", decl: "func Testify(t *testing.T) {}", expect: false},
+		{name: "helper without parameter", decl: "func TestdataPath() string { return \"\" }", expect: false},
+		{name: "wrong parameter type", decl: "func TestFoo(b *testing.B) {}", expect: false},
+		{name: "non-pointer parameter", decl: "func TestFoo(t testing.T) {}", expect: false},
+		{name: "two parameters", decl: "func TestFoo(t *testing.T, s string) {}", expect: false},
+		{name: "two names", decl: "func TestFoo(t, u *testing.T) {}", expect: false},
+		{name: "with results", decl: "func TestFoo(t *testing.T) error { return nil }", expect: false},
+		{name: "method", decl: "func (s *Suite) TestFoo(t *testing.T) {}", expect: false},
+		{name: "type parameters", decl: "func TestFoo[T any](t *testing.T) {}", expect: false},
+		{name: "M for other tests", decl: "func TestFoo(m *testing.M) {}", expect: false},
+		{name: "example with parameter", decl: "func ExampleFoo(t *testing.T) {}", expect: false},
+		{name: "other package", decl: "func TestFoo(t *other.T) {}", expect: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			file, err := parser.ParseFile(token.NewFileSet(), "strict_test.go", "package x\n"+test.decl, 0)
+			require.NoError(t, err)
+
+			fn, ok := file.Decls[0].(*ast.FuncDecl)
+			require.True(t, ok)
+
+			assert.Equal(t, test.expect, AllKinds().matchStrict(fn, "testing"))
+		})
+	}
+}
+
+func Test_Kind_matchStrict_helper(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, KindHelper.matchStrict(parseFuncDecl(t, "func newSquare(t *testing.T) {}"), "testing"))
+	assert.True(t, KindHelper.matchStrict(parseFuncDecl(t, "func Testify() {}"), "testing"))
+	assert.False(t, KindHelper.matchStrict(parseFuncDecl(t, "func TestFoo(t *testing.T) {}"), "testing"))
+	assert.False(t, KindHelper.matchStrict(parseFuncDecl(t, "func (s *Suite) helper() {}"), "testing"))
+}
+
+func Test_Kind_title(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "Tests", KindTest.title())
+	assert.Equal(t, "Fuzz Tests", KindFuzz.title())
+	assert.Equal(t, "Helpers", KindHelper.title())
+	assert.Equal(t, "Kind(-1)", Kind(-1).title())
+}
+
+func Test_Kinds_kindOfStrict(t *testing.T) {
+	t.Parallel()
+
+	kinds := Kinds{KindTest, KindHelper}
+
+	assert.Equal(t, KindTest, kinds.kindOfStrict(parseFuncDecl(t, "func TestFoo(t *testing.T) {}"), "testing"))
+	assert.Equal(t, KindHelper, kinds.kindOfStrict(parseFuncDecl(t, "func Testify() {}"), "testing"),
+		"invalid tests are helpers")
+	assert.Equal(t, KindTest, kinds.kindOfStrict(parseFuncDecl(t, "func (s *Suite) TestFoo() {}"), "testing"),
+		"unmatched functions fall back to their name")
+}
+
+func Test_buildStrictKinds(t *testing.T) {
+	t.Parallel()
+
+	source := `package x
+
+import "testing"
+
+func TestFoo(t *testing.T) {}
+
+func Testify() {}
+`
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "kinds_test.go", source, 0)
+	require.NoError(t, err)
+
+	testFuncPos := map[string][2]int{"TestFoo": {5, 5}, "Testify": {7, 7}}
+	strict := newOptions(WithStrict(), WithKinds(KindTest, KindHelper))
+
+	assert.Equal(t, map[string]Kind{"TestFoo": KindTest, "Testify": KindHelper},
+		buildStrictKinds(file, fset, testFuncPos, strict))
+	assert.Empty(t, buildStrictKinds(file, fset, testFuncPos, newOptions()), "kinds follow the names without Strict")
+}
+
+func Test_hasKindName(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, hasKindName("Test", "Test"))
+	assert.True(t, hasKindName("TestFoo", "Test"))
+	assert.True(t, hasKindName("Test_foo", "Test"))
+	assert.True(t, hasKindName("Test1", "Test"))
+	assert.True(t, hasKindName("TestÄrger", "Test"))
+	assert.False(t, hasKindName("Testify", "Test"))
+	assert.False(t, hasKindName("Testäpfel", "Test"))
+	assert.False(t, hasKindName("Helper", "Test"))
+}
+
+func Test_isTestingPointer(t *testing.T) {
+	t.Parallel()
+
+	aliased := &ast.StarExpr{X: &ast.SelectorExpr{X: ast.NewIdent("tt"), Sel: ast.NewIdent("T")}}
+	dotted := &ast.StarExpr{X: ast.NewIdent("T")}
+
+	assert.True(t, isTestingPointer(aliased, "tt", "T"))
+	assert.False(t, isTestingPointer(aliased, "testing", "T"))
+	assert.True(t, isTestingPointer(dotted, ".", "T"))
+	assert.False(t, isTestingPointer(dotted, "testing", "T"))
+	assert.False(t, isTestingPointer(&ast.StarExpr{X: &ast.ArrayType{}}, "testing", "T"))
+}
+
+func Test_kindOf(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, KindTest, kindOf("TestFoo"))
+	assert.Equal(t, KindBenchmark, kindOf("BenchmarkFoo"))
+	assert.Equal(t, KindFuzz, kindOf("FuzzFoo"))
+	assert.Equal(t, KindExample, kindOf("ExampleFoo_bar"))
+	assert.Equal(t, KindHelper, kindOf("helper"), "unknown prefixes are reported as helpers")
+}
+
+func Test_Kinds_match(t *testing.T) {
+	t.Parallel()
+
+	kinds := Kinds{KindBenchmark, KindExample}
+
+	assert.True(t, kinds.match("BenchmarkFoo"))
+	assert.True(t, kinds.match("Example"))
+	assert.False(t, kinds.match("TestFoo"))
+	assert.False(t, kinds.match("FuzzFoo"))
+}
+
+func Test_Kinds_rank(t *testing.T) {
+	t.Parallel()
+
+	kinds := Kinds{KindFuzz, KindTest}
+
+	assert.Equal(t, 0, kinds.rank(KindFuzz))
+	assert.Equal(t, 1, kinds.rank(KindTest))
+	assert.Equal(t, 2, kinds.rank(KindExample), "unlisted kinds are ranked last")
+}
+
+func Test_testingImportName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		source string
+		expect string
+	}{
+		{name: "default name", source: "package x\nimport \"testing\"", expect: "testing"},
+		{name: "alias", source: "package x\nimport tt \"testing\"", expect: "tt"},
+		{name: "dot import", source: "package x\nimport . \"testing\"", expect: "."},
+		{name: "not imported", source: "package x\nimport \"fmt\"", expect: ""},
+		{name: "other testing package", source: "package x\nimport \"example.com/testing\"", expect: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			file, err := parser.ParseFile(token.NewFileSet(), "import_test.go", test.source, parser.ImportsOnly)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expect, testingImportName(file))
+		})
+	}
+}
+
+func Test_trimKindPrefix(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "Foo", trimKindPrefix("TestFoo"))
+	assert.Equal(t, "foo", trimKindPrefix("Test_foo"))
+	assert.Equal(t, "Foo", trimKindPrefix("Benchmark_Foo"))
+	assert.Equal(t, "Foo", trimKindPrefix("FuzzFoo"))
+	assert.Empty(t, trimKindPrefix("Example"))
+	assert.Equal(t, "helper", trimKindPrefix("helper"))
+}
@@ -0,0 +1,787 @@
+package reorderfuncs
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	// defaultBlankLines is the number of blank lines between sorted functions.
+	defaultBlankLines = 1
+	// defaultFileMode is the permission of the files written by Exec.
+	defaultFileMode os.FileMode = 0o644
+	// testFuncPrefix is the name prefix of the functions reordered by default.
+	testFuncPrefix = "Test"
+	// initFuncName is the name of the package initialization functions.
+	initFuncName = "init"
+)
+
+// ErrInvalidOption is returned when an option value cannot be parsed.
+var ErrInvalidOption = errors.New("invalid option")
+
+// Option configures the reordering behavior.
+type Option func(*Options)
+
+// Options holds the settings applied by Option functions.
+// The zero value reorders test functions alphabetically and places them after
+// all other declarations, which is the default behavior.
+type Options struct {
+	// Mode selects which functions are reordered and how they are grouped.
+	// Default: ModeTests.
+	Mode Mode
+	// Compare compares two function names for sorting. It must return a
+	// negative number, zero or a positive number like strings.Compare.
+	// If nil, strings.Compare is used.
+	Compare func(a, b string) int
+	// SortKey normalizes the function names before they are compared. Names
+	// with equal keys keep their original order. Default: no normalization.
+	SortKey SortKey
+	// Kinds lists the kinds of functions to reorder, in the order their groups
+	// are written. If nil, only test functions (KindTest) are reordered.
+	Kinds Kinds
+	// Match reports whether a function declaration should be reordered.
+	// If nil, functions whose name starts with the prefix of one of the Kinds
+	// are reordered, or every function with ModeFuncs and ModeTypes. The
+	// matched functions of no kind are sorted with the tests, unless
+	// KindHelper is one of the Kinds.
+	Match func(fn *ast.FuncDecl) bool
+	// Strict recognizes the functions of the Kinds the way `go test` does,
+	// checking the name, the receiver, the type parameters and the signature.
+	// For example Testify() or TestFoo(s string) are not reordered. It is
+	// ignored if Match is set.
+	Strict bool
+	// GroupSuites sorts testify suite methods grouped under their receiver
+	// type, with the suite runner function, such as TestFooSuite, first. With
+	// Strict, suite methods such as func (s *FooSuite) TestXxx() are
+	// recognized as tests.
+	GroupSuites bool
+	// TestMain selects how TestMain is positioned. Default: TestMainSorted.
+	TestMain TestMainPolicy
+	// MoveInit allows init functions to be reordered when they are matched.
+	// By default, func init() is never moved.
+	MoveInit bool
+	// Placement selects where the sorted functions are written.
+	// Default: PlaceBottom.
+	Placement Placement
+	// Marker is the anchor comment line, such as "// Tests", after which the
+	// sorted functions are written when Placement is PlaceAfterMarker. Only a
+	// comment at column 1 outside of any declaration is the marker, which
+	// itself never moves.
+	Marker string
+	// SortDecls also moves the top-level type, constant and variable
+	// declarations before the functions, in this order and each keeping their
+	// original order, and sorts the specs of grouped constant and variable
+	// declarations by name. Constant blocks using iota are left untouched. It
+	// only applies with ModeFuncs and ModeTypes, since the functions that are
+	// not tests never move with ModeTests.
+	SortDecls bool
+	// Floating selects what happens to the floating comments, separated from
+	// the following declaration by a blank line, such as commented-out code or
+	// TODO notes. Default: FloatingAttach.
+	Floating FloatingPolicy
+	// Commented selects what happens to the commented-out functions, such as
+	// // func TestOld(t *testing.T) { ... }. Default: CommentedAttach.
+	Commented CommentedPolicy
+	// Banners writes a section banner comment before each group of sorted
+	// functions, such as tests, benchmarks or exported functions. Existing
+	// banners generated from the BannerTemplate are removed and regenerated if
+	// their group is written. Doc comments are never taken for banners.
+	Banners bool
+	// BannerTemplate is the section banner, where "%s" is replaced by the title
+	// of the group. The line holding "%s" must have some other text, such as
+	// "// %s". If empty, DefaultBannerTemplate is used.
+	BannerTemplate string
+	// BlankLines is the number of blank lines between the sorted functions,
+	// and between every top-level declaration with SpacingNormalize.
+	// If zero, one blank line is used.
+	BlankLines int
+	// Spacing selects how the blank lines between the declarations that are
+	// not sorted are written. Default: SpacingPreserve.
+	Spacing SpacingPolicy
+	// Gofmt formats the output with go/format, so that it is gofmt-clean.
+	Gofmt bool
+	// CheckEquivalence also verifies that the output holds the same top-level
+	// declarations with the same doc comments as the source, see Equivalent.
+	CheckEquivalence bool
+	// FileMode is the permission used by Exec to write the output file.
+	// If zero, 0o644 is used.
+	FileMode os.FileMode
+}
+
+// Mode selects which functions are reordered and how they are grouped.
+// It implements flag.Value so it can be used as a command-line flag.
+type Mode int
+
+const (
+	// ModeTests reorders the test functions of the Kinds, grouped by kind.
+	ModeTests Mode = iota
+	// ModeFuncs reorders every top-level function and method of any Go file:
+	// the exported functions first, then the exported methods and then the
+	// unexported functions and methods, each sorted by name.
+	ModeFuncs
+	// ModeTypes reorders the type declarations of any Go file, each followed
+	// by its NewXxx constructors, its exported methods and then its unexported
+	// methods. The free functions come afterwards as with ModeFuncs.
+	ModeTypes
+)
+
+// Placement selects where the sorted functions are written in the output.
+// It implements flag.Value so it can be used as a command-line flag.
+type Placement int
+
+const (
+	// PlaceBottom writes the sorted functions after all other declarations.
+	PlaceBottom Placement = iota
+	// PlaceInPlace permutes the functions only among the slots originally
+	// occupied by them, leaving every other declaration where it was.
+	PlaceInPlace
+	// PlaceTop writes the sorted functions right after the import declarations.
+	PlaceTop
+	// PlaceAfterMarker writes the sorted functions right after the line equal to
+	// Options.Marker. If the marker is not found, PlaceBottom is used.
+	PlaceAfterMarker
+)
+
+// FloatingPolicy selects what happens to the floating comments, which are
+// separated from the following declaration by a blank line. The doc comment
+// directly attached to a declaration always moves along with it.
+// It implements flag.Value so it can be used as a command-line flag.
+type FloatingPolicy int
+
+const (
+	// FloatingAttach moves the floating comments along with the following
+	// declaration.
+	FloatingAttach FloatingPolicy = iota
+	// FloatingKeep never moves the floating comments, keeping them anchored
+	// where they were.
+	FloatingKeep
+	// FloatingPrevious moves the floating comments along with the preceding
+	// declaration.
+	FloatingPrevious
+)
+
+// CommentedPolicy selects what happens to the commented-out functions lying
+// between the declarations, which are the comments whose uncommented text
+// parses as function declarations.
+// It implements flag.Value so it can be used as a command-line flag.
+type CommentedPolicy int
+
+const (
+	// CommentedAttach treats the commented-out functions as any other comment,
+	// see FloatingPolicy.
+	CommentedAttach CommentedPolicy = iota
+	// CommentedKeep never moves the commented-out functions, keeping them
+	// anchored where they were.
+	CommentedKeep
+	// CommentedSort sorts the commented-out functions by their own name, along
+	// with the functions they would be matched with if uncommented.
+	CommentedSort
+)
+
+// SpacingPolicy selects how the blank lines between the top-level declarations,
+// comments and section banners are written.
+// It implements flag.Value so it can be used as a command-line flag.
+type SpacingPolicy int
+
+const (
+	// SpacingPreserve keeps the blank lines around the declarations that are
+	// not sorted as they were. Options.BlankLines only separates the sorted
+	// functions.
+	SpacingPreserve SpacingPolicy = iota
+	// SpacingNormalize writes exactly Options.BlankLines blank lines, one by
+	// default, between every top-level declaration, comment and section
+	// banner. The blank lines inside declarations and comments never change.
+	SpacingNormalize
+)
+
+// TestMainPolicy selects how the TestMain function is positioned.
+// It implements flag.Value so it can be used as a command-line flag.
+type TestMainPolicy int
+
+const (
+	// TestMainSorted sorts TestMain like any other test.
+	TestMainSorted TestMainPolicy = iota
+	// TestMainFirst places TestMain first in the test block.
+	TestMainFirst
+	// TestMainKeep never moves TestMain, keeping it where it was.
+	TestMainKeep
+)
+
+// modeNames maps the modes to their command-line names.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var modeNames = map[Mode]string{
+	ModeTests: "tests",
+	ModeFuncs: "funcs",
+	ModeTypes: "types",
+}
+
+// commentedPolicyNames maps the commented-out function policies to their
+// command-line names.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var commentedPolicyNames = map[CommentedPolicy]string{
+	CommentedAttach: "attach",
+	CommentedKeep:   "keep",
+	CommentedSort:   "sort",
+}
+
+// floatingPolicyNames maps the floating comment policies to their command-line
+// names.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var floatingPolicyNames = map[FloatingPolicy]string{
+	FloatingAttach:   "attach",
+	FloatingKeep:     "keep",
+	FloatingPrevious: "previous",
+}
+
+// spacingPolicyNames maps the spacing policies to their command-line names.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var spacingPolicyNames = map[SpacingPolicy]string{
+	SpacingPreserve:  "preserve",
+	SpacingNormalize: "normalize",
+}
+
+// testMainPolicyNames maps the TestMain policies to their command-line names.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var testMainPolicyNames = map[TestMainPolicy]string{
+	TestMainSorted: "sorted",
+	TestMainFirst:  "first",
+	TestMainKeep:   "keep",
+}
+
+// placementNames maps the placements to their command-line names.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var placementNames = map[Placement]string{
+	PlaceBottom:      "bottom",
+	PlaceInPlace:     "in-place",
+	PlaceTop:         "top",
+	PlaceAfterMarker: "after-marker",
+}
+
+// ============================================================================
+//  Public Functions (ABC Order)
+// ============================================================================
+
+// WithBanners writes a section banner comment before each group of sorted
+// functions, regenerating the existing ones: only the standalone comments
+// made of the banner of a written group, such as "// Tests" with the "// %s"
+// template, are regenerated. If template is empty, DefaultBannerTemplate is
+// used.
+func WithBanners(template string) Option {
+	return func(o *Options) {
+		o.Banners = true
+		o.BannerTemplate = template
+	}
+}
+
+// WithBlankLines sets the number of blank lines between the sorted functions.
+func WithBlankLines(n int) Option {
+	return func(o *Options) {
+		o.BlankLines = n
+	}
+}
+
+// WithCheckEquivalence verifies that the output holds the same top-level
+// declarations with the same doc comments as the source, see Equivalent.
+func WithCheckEquivalence() Option {
+	return func(o *Options) {
+		o.CheckEquivalence = true
+	}
+}
+
+// WithCommented sets what happens to the commented-out functions: treated as
+// any other comment, kept in place or sorted by their own name.
+func WithCommented(policy CommentedPolicy) Option {
+	return func(o *Options) {
+		o.Commented = policy
+	}
+}
+
+// WithCompare sets the function used to compare function names for sorting.
+func WithCompare(compare func(a, b string) int) Option {
+	return func(o *Options) {
+		o.Compare = compare
+	}
+}
+
+// WithFileMode sets the permission used by Exec to write the output file.
+func WithFileMode(mode os.FileMode) Option {
+	return func(o *Options) {
+		o.FileMode = mode
+	}
+}
+
+// WithFloating sets what happens to the floating comments, separated from the
+// following declaration by a blank line: moved along with it, kept in place or
+// moved along with the preceding declaration.
+func WithFloating(policy FloatingPolicy) Option {
+	return func(o *Options) {
+		o.Floating = policy
+	}
+}
+
+// WithGofmt formats the output with go/format, so that it is gofmt-clean.
+func WithGofmt() Option {
+	return func(o *Options) {
+		o.Gofmt = true
+	}
+}
+
+// WithGroupSuites sorts testify suite methods grouped under their receiver
+// type, with the suite runner function first.
+func WithGroupSuites() Option {
+	return func(o *Options) {
+		o.GroupSuites = true
+	}
+}
+
+// WithKinds sets the kinds of functions to reorder, in the order their groups
+// are written, e.g. WithKinds(AllKinds()...) for tests, benchmarks, fuzz tests
+// and then examples.
+func WithKinds(kinds ...Kind) Option {
+	return func(o *Options) {
+		o.Kinds = kinds
+	}
+}
+
+// WithMarker places the sorted functions right after the given anchor comment
+// line, such as "// Tests". It is a shorthand for setting both Options.Marker
+// and PlaceAfterMarker.
+func WithMarker(marker string) Option {
+	return func(o *Options) {
+		o.Marker = marker
+		o.Placement = PlaceAfterMarker
+	}
+}
+
+// WithMatch sets the predicate that selects the functions to reorder. The
+// matched functions of no kind, such as checkFoo, are sorted with the tests.
+func WithMatch(match func(fn *ast.FuncDecl) bool) Option {
+	return func(o *Options) {
+		o.Match = match
+	}
+}
+
+// WithMode sets which functions are reordered and how they are grouped, e.g.
+// WithMode(ModeFuncs) to order all the functions of a non-test file.
+func WithMode(mode Mode) Option {
+	return func(o *Options) {
+		o.Mode = mode
+	}
+}
+
+// WithMoveInit allows init functions to be reordered when they are matched.
+// By default, func init() is never moved.
+func WithMoveInit() Option {
+	return func(o *Options) {
+		o.MoveInit = true
+	}
+}
+
+// WithNaturalSort sorts function names in natural order, comparing runs of
+// digits numerically. It is a shorthand for WithCompare(CompareNatural).
+func WithNaturalSort() Option {
+	return WithCompare(CompareNatural)
+}
+
+// WithOptions replaces all the settings with the given Options. It is useful
+// to pass a pre-built Options, such as one filled from command-line flags.
+func WithOptions(options Options) Option {
+	return func(o *Options) {
+		*o = options
+	}
+}
+
+// WithPlacement sets where the sorted functions are written.
+func WithPlacement(placement Placement) Option {
+	return func(o *Options) {
+		o.Placement = placement
+	}
+}
+
+// WithSortDecls moves the top-level type, constant and variable declarations
+// before the functions and sorts the specs of grouped constant and variable
+// declarations by name, leaving iota-based constant blocks untouched. It is
+// ignored with ModeTests.
+func WithSortDecls() Option {
+	return func(o *Options) {
+		o.SortDecls = true
+	}
+}
+
+// WithSortKey sets the normalizations applied to function names before they
+// are compared, e.g. WithSortKey(SortKeyFoldCase|SortKeyIgnoreUnderscore).
+func WithSortKey(key SortKey) Option {
+	return func(o *Options) {
+		o.SortKey = key
+	}
+}
+
+// WithSpacing sets how the blank lines between the top-level declarations are
+// written, e.g. WithSpacing(SpacingNormalize) with WithBlankLines(2) for exactly
+// two blank lines between every declaration.
+func WithSpacing(policy SpacingPolicy) Option {
+	return func(o *Options) {
+		o.Spacing = policy
+	}
+}
+
+// WithStrict recognizes the functions to reorder the way `go test` does,
+// checking their name, receiver, type parameters and signature.
+func WithStrict() Option {
+	return func(o *Options) {
+		o.Strict = true
+	}
+}
+
+// WithTestMain sets how TestMain is positioned: sorted like any other test,
+// first in the test block or kept where it was.
+func WithTestMain(policy TestMainPolicy) Option {
+	return func(o *Options) {
+		o.TestMain = policy
+	}
+}
+
+// ============================================================================
+//  Methods (ABC Order)
+// ============================================================================
+
+// Set implements flag.Value. It parses the commented-out function policy from
+// its name.
+func (c *CommentedPolicy) Set(name string) error {
+	for policy, policyName := range commentedPolicyNames {
+		if policyName == name {
+			*c = policy
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: unknown commented-out function policy %q", ErrInvalidOption, name)
+}
+
+// String implements fmt.Stringer and flag.Value.
+func (c CommentedPolicy) String() string {
+	if name, ok := commentedPolicyNames[c]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("CommentedPolicy(%d)", int(c))
+}
+
+// Set implements flag.Value. It parses the floating comment policy from its name.
+func (f *FloatingPolicy) Set(name string) error {
+	for policy, policyName := range floatingPolicyNames {
+		if policyName == name {
+			*f = policy
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: unknown floating comment policy %q", ErrInvalidOption, name)
+}
+
+// String implements fmt.Stringer and flag.Value.
+func (f FloatingPolicy) String() string {
+	if name, ok := floatingPolicyNames[f]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("FloatingPolicy(%d)", int(f))
+}
+
+// Set implements flag.Value. It parses the mode from its name.
+func (m *Mode) Set(name string) error {
+	for mode, modeName := range modeNames {
+		if modeName == name {
+			*m = mode
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: unknown mode %q", ErrInvalidOption, name)
+}
+
+// String implements fmt.Stringer and flag.Value.
+func (m Mode) String() string {
+	if name, ok := modeNames[m]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Mode(%d)", int(m))
+}
+
+// Set implements flag.Value. It parses the placement from its name.
+func (p *Placement) Set(name string) error {
+	for placement, placementName := range placementNames {
+		if placementName == name {
+			*p = placement
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: unknown placement %q", ErrInvalidOption, name)
+}
+
+// String implements fmt.Stringer and flag.Value.
+func (p Placement) String() string {
+	if name, ok := placementNames[p]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Placement(%d)", int(p))
+}
+
+// Set implements flag.Value. It parses the spacing policy from its name.
+func (s *SpacingPolicy) Set(name string) error {
+	for policy, policyName := range spacingPolicyNames {
+		if policyName == name {
+			*s = policy
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: unknown spacing policy %q", ErrInvalidOption, name)
+}
+
+// String implements fmt.Stringer and flag.Value.
+func (s SpacingPolicy) String() string {
+	if name, ok := spacingPolicyNames[s]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("SpacingPolicy(%d)", int(s))
+}
+
+// Set implements flag.Value. It parses the TestMain policy from its name.
+func (t *TestMainPolicy) Set(name string) error {
+	for policy, policyName := range testMainPolicyNames {
+		if policyName == name {
+			*t = policy
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: unknown TestMain policy %q", ErrInvalidOption, name)
+}
+
+// String implements fmt.Stringer and flag.Value.
+func (t TestMainPolicy) String() string {
+	if name, ok := testMainPolicyNames[t]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("TestMainPolicy(%d)", int(t))
+}
+
+// ============================================================================
+//  Private Functions (ABC Order)
+// ============================================================================
+
+// blankLines returns the number of blank lines between the sorted functions.
+func (o Options) blankLines() int {
+	if o.BlankLines <= 0 {
+		return defaultBlankLines
+	}
+
+	return o.BlankLines
+}
+
+// compare compares two function names using the configured sort key and comparison.
+func (o Options) compare(a, b string) int {
+	a, b = o.SortKey.Apply(a), o.SortKey.Apply(b)
+
+	if o.Compare == nil {
+		return strings.Compare(a, b)
+	}
+
+	return o.Compare(a, b)
+}
+
+// declRank returns the rank of the declaration with SortDecls: types first,
+// then constants, variables and functions. With ModeTypes, the functions of a
+// type are ranked with the types.
+func (o Options) declRank(testFunc TestFunction) int {
+	if !o.sortsDecls() {
+		return rankTypes
+	}
+
+	switch {
+	case testFunc.Token == token.TYPE, o.Mode == ModeTypes && testFunc.Type != "":
+		return rankTypes
+	case testFunc.Token == token.CONST:
+		return rankConsts
+	case testFunc.Token == token.VAR:
+		return rankVars
+	default:
+		return rankFuncs
+	}
+}
+
+// fileMode returns the permission used to write the output file.
+func (o Options) fileMode() os.FileMode {
+	if o.FileMode == 0 {
+		return defaultFileMode
+	}
+
+	return o.FileMode
+}
+
+// firstRank returns 0 for the function placed first in its group, TestMain with
+// TestMainFirst, and 1 for any other function.
+func (o Options) firstRank(testFunc TestFunction) int {
+	if o.TestMain == TestMainFirst && testFunc.Name == testMainName && testFunc.Receiver == "" {
+		return 0
+	}
+
+	return 1
+}
+
+// groupRank returns the rank of the group the function is written in: the
+// position of its kind in the Kinds, or with ModeFuncs 0 for exported
+// functions, 1 for exported methods and 2 for unexported functions and methods.
+// With ModeTypes, the types and their functions come first, ranked 0, and the
+// free functions are ranked after them.
+func (o Options) groupRank(testFunc TestFunction) int {
+	if o.Mode == ModeTests {
+		return o.kinds().rank(testFunc.Kind)
+	}
+
+	rank := 0
+
+	if o.Mode == ModeTypes {
+		if testFunc.Type != "" {
+			return 0
+		}
+
+		rank++
+	}
+
+	switch {
+	case !token.IsExported(testFunc.Name):
+		rank += 2
+	case testFunc.Receiver != "":
+		rank++
+	}
+
+	return rank
+}
+
+// isMarker reports whether the line is the placement marker comment, starting
+// at column 1.
+func (o Options) isMarker(line string) bool {
+	if o.Placement != PlaceAfterMarker {
+		return false
+	}
+
+	marker := strings.TrimSpace(o.Marker)
+
+	return marker != "" && strings.TrimRightFunc(line, unicode.IsSpace) == marker
+}
+
+// isPinned reports whether the function must never be moved.
+func (o Options) isPinned(fn *ast.FuncDecl) bool {
+	if fn.Recv != nil {
+		return false
+	}
+
+	switch fn.Name.Name {
+	case initFuncName:
+		return !o.MoveInit
+	case testMainName:
+		return o.TestMain == TestMainKeep
+	default:
+		return false
+	}
+}
+
+// keepsDeclOrder reports whether the declaration keeps its original order
+// among the declarations of its class: general declarations are not sorted,
+// except types with ModeTypes.
+func (o Options) keepsDeclOrder(testFunc TestFunction) bool {
+	switch testFunc.Token {
+	case token.CONST, token.VAR:
+		return true
+	case token.TYPE:
+		return o.Mode != ModeTypes
+	default:
+		return false
+	}
+}
+
+// kindOf returns the kind of a function from its name prefix, see kindOf. With
+// Match, the matched functions of no other kind are sorted with the tests,
+// unless KindHelper is one of the Kinds.
+func (o Options) kindOf(name string) Kind {
+	kind := kindOf(name)
+	if kind == KindHelper && o.Match != nil && !slices.Contains(o.kinds(), KindHelper) {
+		return KindTest
+	}
+
+	return kind
+}
+
+// kinds returns the kinds of functions to reorder.
+func (o Options) kinds() Kinds {
+	if o.Kinds == nil {
+		return Kinds{KindTest}
+	}
+
+	return o.Kinds
+}
+
+// match reports whether the function declaration should be reordered. The
+// testingName is the name the testing package is imported as in the file.
+// Pinned functions, init and possibly TestMain, are never reordered.
+func (o Options) match(fn *ast.FuncDecl, testingName string) bool {
+	if o.isPinned(fn) {
+		return false
+	}
+
+	switch {
+	case o.Match != nil:
+		return o.Match(fn)
+	case o.Mode == ModeFuncs, o.Mode == ModeTypes:
+		return true
+	case o.Strict:
+		return o.kinds().matchStrict(fn, testingName) || (o.GroupSuites && isSuiteMethod(fn))
+	default:
+		return o.kinds().match(fn.Name.Name)
+	}
+}
+
+// newOptions applies the given Option functions to a zero Options.
+func newOptions(opts ...Option) Options {
+	var options Options
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+
+	return options
+}
+
+// sortsDecls reports whether the general declarations are moved and their
+// grouped specs sorted: SortDecls only applies when every function is moved,
+// with ModeFuncs and ModeTypes.
+func (o Options) sortsDecls() bool {
+	return o.SortDecls && o.Mode != ModeTests
+}
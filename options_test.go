@@ -0,0 +1,447 @@
+package reorderfuncs
+
+import (
+	"go/ast"
+	"go/token"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+//	Public Functions (ABC Order)
+// ============================================================================
+
+func TestCommentedPolicy_Set(t *testing.T) {
+	t.Parallel()
+
+	var policy CommentedPolicy
+
+	require.NoError(t, policy.Set("keep"))
+	assert.Equal(t, CommentedKeep, policy)
+
+	require.NoError(t, policy.Set("sort"))
+	assert.Equal(t, CommentedSort, policy)
+
+	require.NoError(t, policy.Set("attach"))
+	assert.Equal(t, CommentedAttach, policy)
+
+	err := policy.Set("remove")
+
+	require.ErrorIs(t, err, ErrInvalidOption)
+	assert.Contains(t, err.Error(), `unknown commented-out function policy "remove"`)
+}
+
+func TestCommentedPolicy_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "attach", CommentedAttach.String())
+	assert.Equal(t, "keep", CommentedKeep.String())
+	assert.Equal(t, "sort", CommentedSort.String())
+	assert.Equal(t, "CommentedPolicy(-1)", CommentedPolicy(-1).String())
+}
+
+func TestFloatingPolicy_Set(t *testing.T) {
+	t.Parallel()
+
+	var policy FloatingPolicy
+
+	require.NoError(t, policy.Set("keep"))
+	assert.Equal(t, FloatingKeep, policy)
+
+	require.NoError(t, policy.Set("previous"))
+	assert.Equal(t, FloatingPrevious, policy)
+
+	require.NoError(t, policy.Set("attach"))
+	assert.Equal(t, FloatingAttach, policy)
+
+	err := policy.Set("detach")
+
+	require.ErrorIs(t, err, ErrInvalidOption)
+	assert.Contains(t, err.Error(), `unknown floating comment policy "detach"`)
+}
+
+func TestFloatingPolicy_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "attach", FloatingAttach.String())
+	assert.Equal(t, "keep", FloatingKeep.String())
+	assert.Equal(t, "previous", FloatingPrevious.String())
+	assert.Equal(t, "FloatingPolicy(-1)", FloatingPolicy(-1).String())
+}
+
+func TestMode_Set(t *testing.T) {
+	t.Parallel()
+
+	var mode Mode
+
+	require.NoError(t, mode.Set("funcs"))
+	assert.Equal(t, ModeFuncs, mode)
+
+	require.NoError(t, mode.Set("types"))
+	assert.Equal(t, ModeTypes, mode)
+
+	require.NoError(t, mode.Set("tests"))
+	assert.Equal(t, ModeTests, mode)
+
+	err := mode.Set("layers")
+
+	require.ErrorIs(t, err, ErrInvalidOption)
+	assert.Contains(t, err.Error(), `unknown mode "layers"`)
+}
+
+func TestMode_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "tests", ModeTests.String())
+	assert.Equal(t, "funcs", ModeFuncs.String())
+	assert.Equal(t, "types", ModeTypes.String())
+	assert.Equal(t, "Mode(-1)", Mode(-1).String())
+}
+
+func TestPlacement_Set(t *testing.T) {
+	t.Parallel()
+
+	var placement Placement
+
+	require.NoError(t, placement.Set("in-place"))
+	assert.Equal(t, PlaceInPlace, placement)
+
+	require.NoError(t, placement.Set("top"))
+	assert.Equal(t, PlaceTop, placement)
+
+	require.NoError(t, placement.Set("after-marker"))
+	assert.Equal(t, PlaceAfterMarker, placement)
+
+	require.NoError(t, placement.Set("bottom"))
+	assert.Equal(t, PlaceBottom, placement)
+
+	err := placement.Set("unknown")
+
+	require.ErrorIs(t, err, ErrInvalidOption)
+	assert.Contains(t, err.Error(), `unknown placement "unknown"`)
+}
+
+func TestPlacement_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "bottom", PlaceBottom.String())
+	assert.Equal(t, "in-place", PlaceInPlace.String())
+	assert.Equal(t, "top", PlaceTop.String())
+	assert.Equal(t, "after-marker", PlaceAfterMarker.String())
+	assert.Equal(t, "Placement(-1)", Placement(-1).String())
+}
+
+func TestSpacingPolicy_Set(t *testing.T) {
+	t.Parallel()
+
+	var policy SpacingPolicy
+
+	require.NoError(t, policy.Set("normalize"))
+	assert.Equal(t, SpacingNormalize, policy)
+
+	require.NoError(t, policy.Set("preserve"))
+	assert.Equal(t, SpacingPreserve, policy)
+
+	err := policy.Set("compact")
+
+	require.ErrorIs(t, err, ErrInvalidOption)
+	assert.Contains(t, err.Error(), `unknown spacing policy "compact"`)
+}
+
+func TestSpacingPolicy_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "preserve", SpacingPreserve.String())
+	assert.Equal(t, "normalize", SpacingNormalize.String())
+	assert.Equal(t, "SpacingPolicy(-1)", SpacingPolicy(-1).String())
+}
+
+func TestTestMainPolicy_Set(t *testing.T) {
+	t.Parallel()
+
+	var policy TestMainPolicy
+
+	require.NoError(t, policy.Set("first"))
+	assert.Equal(t, TestMainFirst, policy)
+
+	require.NoError(t, policy.Set("keep"))
+	assert.Equal(t, TestMainKeep, policy)
+
+	require.NoError(t, policy.Set("sorted"))
+	assert.Equal(t, TestMainSorted, policy)
+
+	err := policy.Set("last")
+
+	require.ErrorIs(t, err, ErrInvalidOption)
+	assert.Contains(t, err.Error(), `unknown TestMain policy "last"`)
+}
+
+func TestTestMainPolicy_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "sorted", TestMainSorted.String())
+	assert.Equal(t, "first", TestMainFirst.String())
+	assert.Equal(t, "keep", TestMainKeep.String())
+	assert.Equal(t, "TestMainPolicy(-1)", TestMainPolicy(-1).String())
+}
+
+func TestWithBanners(t *testing.T) {
+	t.Parallel()
+
+	options := newOptions(WithBanners("// %s"))
+
+	assert.True(t, options.Banners)
+	assert.Equal(t, "// %s", options.BannerTemplate)
+	assert.Equal(t, []string{"// Tests"}, bannerLines("Tests", options))
+	assert.Len(t, bannerLines("Tests", newOptions(WithBanners(""))), 3, "default template")
+}
+
+func TestWithCheckEquivalence(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, newOptions().CheckEquivalence)
+	assert.True(t, newOptions(WithCheckEquivalence()).CheckEquivalence)
+}
+
+func TestWithCommented(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, CommentedAttach, newOptions().Commented)
+	assert.Equal(t, CommentedSort, newOptions(WithCommented(CommentedSort)).Commented)
+}
+
+func TestWithFloating(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, FloatingAttach, newOptions().Floating)
+	assert.Equal(t, FloatingKeep, newOptions(WithFloating(FloatingKeep)).Floating)
+}
+
+func TestWithGofmt(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, newOptions().Gofmt)
+	assert.True(t, newOptions(WithGofmt()).Gofmt)
+}
+
+func TestWithGroupSuites(t *testing.T) {
+	t.Parallel()
+
+	method := &ast.FuncDecl{
+		Recv: &ast.FieldList{List: []*ast.Field{{Type: ast.NewIdent("FooSuite")}}},
+		Name: ast.NewIdent("TestCreate"),
+		Type: &ast.FuncType{Params: &ast.FieldList{}},
+	}
+
+	assert.False(t, newOptions(WithStrict()).match(method, "testing"),
+		"strict mode alone does not recognize methods")
+	assert.True(t, newOptions(WithStrict(), WithGroupSuites()).match(method, "testing"),
+		"strict mode recognizes suite methods when grouping suites")
+}
+
+func TestWithKinds(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, Kinds{KindTest}, newOptions().kinds(), "only tests are reordered by default")
+
+	options := newOptions(WithKinds(KindBenchmark, KindTest))
+
+	assert.Equal(t, Kinds{KindBenchmark, KindTest}, options.kinds())
+	assert.True(t, options.match(&ast.FuncDecl{Name: ast.NewIdent("BenchmarkFoo")}, "testing"))
+	assert.False(t, options.match(&ast.FuncDecl{Name: ast.NewIdent("ExampleFoo")}, "testing"))
+}
+
+func TestWithMarker(t *testing.T) {
+	t.Parallel()
+
+	options := newOptions(WithMarker("// Tests"))
+
+	assert.Equal(t, PlaceAfterMarker, options.Placement)
+	assert.True(t, options.isMarker("// Tests  "))
+	assert.False(t, options.isMarker("\t// Tests"), "the marker starts at column 1")
+	assert.False(t, options.isMarker("// Other"))
+	assert.False(t, newOptions(WithMarker("")).isMarker(""), "empty marker never matches")
+}
+
+func TestWithMoveInit(t *testing.T) {
+	t.Parallel()
+
+	initFunc := &ast.FuncDecl{Name: ast.NewIdent("init")}
+	matchAll := WithMatch(func(*ast.FuncDecl) bool { return true })
+
+	assert.False(t, newOptions(matchAll).match(initFunc, "testing"), "init is never moved by default")
+	assert.True(t, newOptions(matchAll, WithMoveInit()).match(initFunc, "testing"))
+}
+
+func TestWithMatch(t *testing.T) {
+	t.Parallel()
+
+	match := WithMatch(func(*ast.FuncDecl) bool { return true })
+
+	assert.Equal(t, KindHelper, newOptions().kindOf("CheckFoo"))
+	assert.Equal(t, KindTest, newOptions(match).kindOf("CheckFoo"), "matched functions are sorted with the tests")
+	assert.Equal(t, KindBenchmark, newOptions(match).kindOf("BenchmarkFoo"))
+	assert.Equal(t, KindHelper, newOptions(match, WithKinds(KindTest, KindHelper)).kindOf("CheckFoo"))
+
+	source := "package x\n\nfunc TestZ() {}\n\nfunc CheckB() {}\n\nfunc TestA() {}\n"
+	expect := "package x\n\nfunc CheckB() {}\n\nfunc TestA() {}\n\nfunc TestZ() {}\n"
+
+	actual, err := ReorderSource("match_test.go", []byte(source), match)
+
+	require.NoError(t, err)
+	assert.Equal(t, expect, string(actual))
+}
+
+func TestWithMode(t *testing.T) {
+	t.Parallel()
+
+	helper := &ast.FuncDecl{Name: ast.NewIdent("helper")}
+
+	assert.False(t, newOptions().match(helper, "testing"))
+	assert.True(t, newOptions(WithMode(ModeFuncs)).match(helper, "testing"))
+
+	funcs := newOptions(WithMode(ModeFuncs))
+
+	assert.Equal(t, 0, funcs.groupRank(TestFunction{Name: "Describe"}))
+	assert.Equal(t, 1, funcs.groupRank(TestFunction{Name: "Area", Receiver: "circle"}),
+		"the exported methods have their own group")
+	assert.Equal(t, 2, funcs.groupRank(TestFunction{Name: "describe"}))
+	assert.Equal(t, 2, funcs.groupRank(TestFunction{Name: "scale", Receiver: "circle"}),
+		"the unexported methods are grouped with the unexported functions")
+
+	types := newOptions(WithMode(ModeTypes))
+
+	assert.True(t, types.match(helper, "testing"))
+	assert.Equal(t, 0, types.groupRank(TestFunction{Name: "lock", Receiver: "Store", Type: "Store"}))
+	assert.Equal(t, 1, types.groupRank(TestFunction{Name: "Open"}))
+	assert.Equal(t, 3, types.groupRank(TestFunction{Name: "validate"}))
+}
+
+func TestWithNaturalSort(t *testing.T) {
+	t.Parallel()
+
+	options := newOptions(WithNaturalSort())
+
+	assert.Negative(t, options.compare("Test_case2", "Test_case10"))
+}
+
+func TestWithSortKey(t *testing.T) {
+	t.Parallel()
+
+	options := newOptions(WithSortKey(SortKeyFoldCase), WithNaturalSort())
+
+	assert.Negative(t, options.compare("Test_case2", "test_CASE10"),
+		"sort key should be applied before the comparison")
+}
+
+func TestWithSortDecls(t *testing.T) {
+	t.Parallel()
+
+	options := newOptions(WithSortDecls(), WithMode(ModeFuncs))
+
+	assert.True(t, options.SortDecls)
+	assert.Equal(t, rankTypes, options.declRank(TestFunction{Name: "Config", Token: token.TYPE}))
+	assert.Equal(t, rankConsts, options.declRank(TestFunction{Name: "limit", Token: token.CONST}))
+	assert.Equal(t, rankVars, options.declRank(TestFunction{Name: "cache", Token: token.VAR}))
+	assert.Equal(t, rankFuncs, options.declRank(TestFunction{Name: "Load"}))
+	assert.Equal(t, rankTypes, newOptions().declRank(TestFunction{Name: "cache", Token: token.VAR}),
+		"declarations are not ranked without SortDecls")
+	assert.Equal(t, rankTypes, newOptions(WithSortDecls()).declRank(TestFunction{Name: "cache", Token: token.VAR}),
+		"declarations are not ranked with ModeTests")
+
+	types := newOptions(WithSortDecls(), WithMode(ModeTypes))
+
+	assert.Equal(t, rankTypes, types.declRank(TestFunction{Name: "Get", Receiver: "Store", Type: "Store"}))
+	assert.True(t, options.keepsDeclOrder(TestFunction{Name: "Config", Token: token.TYPE}))
+	assert.False(t, types.keepsDeclOrder(TestFunction{Name: "Config", Token: token.TYPE}), "types are sorted by name")
+	assert.True(t, types.keepsDeclOrder(TestFunction{Name: "cache", Token: token.VAR}))
+	assert.False(t, options.keepsDeclOrder(TestFunction{Name: "Load"}))
+}
+
+func TestWithSpacing(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, SpacingPreserve, newOptions().Spacing)
+	assert.Equal(t, SpacingNormalize, newOptions(WithSpacing(SpacingNormalize)).Spacing)
+}
+
+func TestWithStrict(t *testing.T) {
+	t.Parallel()
+
+	options := newOptions(WithStrict())
+	helper := &ast.FuncDecl{Name: ast.NewIdent("Testify"), Type: &ast.FuncType{Params: &ast.FieldList{}}}
+
+	assert.True(t, options.Strict)
+	assert.False(t, options.match(helper, "testing"))
+	assert.True(t, newOptions().match(helper, "testing"), "lenient mode only checks the prefix")
+}
+
+func TestWithTestMain(t *testing.T) {
+	t.Parallel()
+
+	testMain := &ast.FuncDecl{Name: ast.NewIdent("TestMain")}
+
+	assert.True(t, newOptions().match(testMain, "testing"))
+	assert.True(t, newOptions(WithTestMain(TestMainFirst)).match(testMain, "testing"))
+	assert.False(t, newOptions(WithTestMain(TestMainKeep)).match(testMain, "testing"),
+		"TestMain is not moved when kept")
+
+	first := newOptions(WithTestMain(TestMainFirst))
+
+	assert.Equal(t, 0, first.firstRank(TestFunction{Name: "TestMain"}))
+	assert.Equal(t, 1, first.firstRank(TestFunction{Name: "TestMain", Receiver: "FooSuite"}))
+	assert.Equal(t, 1, first.firstRank(TestFunction{Name: "TestAlpha"}))
+	assert.Equal(t, 1, newOptions().firstRank(TestFunction{Name: "TestMain"}))
+}
+
+func TestWithOptions(t *testing.T) {
+	t.Parallel()
+
+	options := newOptions(
+		WithBlankLines(3),
+		WithOptions(Options{BlankLines: 2, FileMode: 0o600}),
+	)
+
+	assert.Equal(t, 2, options.BlankLines, "WithOptions should replace previous settings")
+	assert.Equal(t, os.FileMode(0o600), options.FileMode)
+}
+
+// ============================================================================
+//	Private Functions (ABC Order)
+// ============================================================================
+
+func Test_newOptions_defaults(t *testing.T) {
+	t.Parallel()
+
+	options := newOptions()
+
+	assert.Equal(t, defaultBlankLines, options.blankLines())
+	assert.Equal(t, defaultFileMode, options.fileMode())
+	assert.Equal(t, PlaceBottom, options.Placement)
+	assert.Negative(t, options.compare("Test_a", "Test_b"))
+	assert.True(t, options.match(&ast.FuncDecl{Name: ast.NewIdent("Test_a")}, "testing"))
+	assert.False(t, options.match(&ast.FuncDecl{Name: ast.NewIdent("helper")}, "testing"))
+}
+
+func Test_newOptions_overrides(t *testing.T) {
+	t.Parallel()
+
+	options := newOptions(
+		WithBlankLines(2),
+		WithCompare(func(a, b string) int { return strings.Compare(b, a) }),
+		WithFileMode(0o600),
+		WithMatch(func(fn *ast.FuncDecl) bool { return fn.Name.Name == "helper" }),
+		WithPlacement(PlaceBottom),
+		nil, // nil options are ignored
+	)
+
+	assert.Equal(t, 2, options.blankLines())
+	assert.Equal(t, os.FileMode(0o600), options.fileMode())
+	assert.Positive(t, options.compare("Test_a", "Test_b"), "compare should be reversed")
+	assert.False(t, options.match(&ast.FuncDecl{Name: ast.NewIdent("Test_a")}, "testing"))
+	assert.True(t, options.match(&ast.FuncDecl{Name: ast.NewIdent("helper")}, "testing"))
+}
@@ -2,24 +2,44 @@
 package reorderfuncs
 
 import (
+	"cmp"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"io"
 	"os"
 	"sort"
 	"strings"
 )
 
-const (
-	// importBlockStart represents the start of a multi-line import block.
-	importBlockStart = "import ("
-)
-
 // TestFunction represents a test function with its content.
 type TestFunction struct {
 	Name  string
 	Lines []string
+	// Kind is the kind of the function, such as KindTest or KindBenchmark.
+	// Functions are grouped by kind in the order of Options.Kinds.
+	Kind Kind
+	// Receiver is the receiver type name of methods, such as "FooSuite" for
+	// func (s *FooSuite) TestCreate(). It is empty for functions.
+	Receiver string
+	// Suite is the receiver type of the testify suite the function belongs to:
+	// the receiver of a method, or the suite run by a runner function such as
+	// TestFooSuite. It is used by Options.GroupSuites.
+	Suite string
+	// Type is the type the declaration belongs to with ModeTypes: the declared
+	// type itself, for type declarations whose Name is the type name, the
+	// receiver of a method or the type returned by a NewXxx constructor. It is
+	// empty for free functions.
+	Type string
+	// Token is the keyword of the general declarations moved with
+	// Options.SortDecls or ModeTypes: token.TYPE, token.CONST or token.VAR.
+	// It is token.ILLEGAL, the zero value, for functions.
+	Token token.Token
+	// Offset is the index in the non-test lines at which the function was
+	// found in the source. It is used by PlaceInPlace to put the sorted
+	// functions back into the slots originally occupied by functions.
+	Offset int
 }
 
 // ============================================================================
@@ -27,44 +47,37 @@ type TestFunction struct {
 // ============================================================================
 
 // BuildOutputContent constructs the final output content from test functions and non-test lines.
-func BuildOutputContent(testFuncs []TestFunction, nonTestLines []string) string {
-	// Sort test functions alphabetically
-	sort.Slice(testFuncs, func(i, j int) bool {
-		return testFuncs[i].Name < testFuncs[j].Name
-	})
-
-	// Build output content
-	var outputLines []string
-
-	outputLines = append(outputLines, nonTestLines...)
+// The lines are joined with "\n": sources with "\r\n" line endings are not
+// supported, ReorderSource restores them.
+func BuildOutputContent(testFuncs []TestFunction, nonTestLines []string, opts ...Option) string {
+	options := newOptions(opts...)
 
-	// Add empty line before test functions if there are any
-	if len(testFuncs) > 0 && len(nonTestLines) > 0 {
-		// Remove trailing empty lines from nonTestLines
-		for len(outputLines) > 0 && strings.TrimSpace(outputLines[len(outputLines)-1]) == "" {
-			outputLines = outputLines[:len(outputLines)-1]
-		}
-
-		outputLines = append(outputLines, "")
+	if options.Banners {
+		testFuncs, nonTestLines = removeBanners(testFuncs, nonTestLines, options)
 	}
 
-	// Add sorted test functions
-	for i, testFunc := range testFuncs {
-		if i > 0 {
-			outputLines = append(outputLines, "")
-		}
+	var outputLines []string
 
-		// Remove leading empty lines from function content
-		funcLines := testFunc.Lines
-		for len(funcLines) > 0 && strings.TrimSpace(funcLines[0]) == "" {
-			funcLines = funcLines[1:]
-		}
+	switch options.Placement {
+	case PlaceInPlace:
+		outputLines = buildInPlace(testFuncs, nonTestLines, options)
+	case PlaceTop:
+		nonTestLines = closeGaps(testFuncs, nonTestLines, options)
+		outputLines = buildAtTop(testFuncs, nonTestLines, options)
+	case PlaceAfterMarker:
+		nonTestLines = closeGaps(testFuncs, nonTestLines, options)
+		outputLines = buildAfterMarker(testFuncs, nonTestLines, options)
+	default:
+		nonTestLines = closeGaps(testFuncs, nonTestLines, options)
+		outputLines = buildAtBottom(testFuncs, nonTestLines, options)
+	}
 
-		outputLines = append(outputLines, funcLines...)
+	if options.Spacing == SpacingNormalize {
+		outputLines = normalizeBlankLines(outputLines, options.blankLines())
 	}
 
-	// Join and ensure final newline
-	output := strings.Join(outputLines, "\n")
+	// Join and ensure a single final newline
+	output := strings.Join(trimTrailingBlankLines(outputLines), "\n")
 	if !strings.HasSuffix(output, "\n") {
 		output += "\n"
 	}
@@ -72,23 +85,20 @@ func BuildOutputContent(testFuncs []TestFunction, nonTestLines []string) string
 	return output
 }
 
-// Exec reorders test functions in a Go source file alphabetically.
-func Exec(pathInput, pathOutput string) error {
-	// Parse the Go file
-	lines, file, fset, err := ParseGoFile(pathInput)
+// Exec reorders the functions of a Go source file selected by the options, the
+// test functions sorted by name by default, and writes the result.
+func Exec(pathInput, pathOutput string, opts ...Option) error {
+	src, err := os.ReadFile(pathInput) //nolint:gosec // Input path is controlled by caller
 	if err != nil {
-		return err // Error already includes proper context from ParseGoFile
+		return fmt.Errorf("failed to read input file: %w", err)
 	}
 
-	// Extract test functions and non-test content
-	testFuncs, nonTestLines := ExtractTestFunctions(lines, file, fset)
-
-	// Build output content
-	output := BuildOutputContent(testFuncs, nonTestLines)
-
-	const defaultFileMode = 0o644
+	output, err := ReorderSource(pathInput, src, opts...)
+	if err != nil {
+		return err // Error already includes proper context from ReorderSource
+	}
 
-	err = os.WriteFile(pathOutput, []byte(output), defaultFileMode)
+	err = os.WriteFile(pathOutput, output, newOptions(opts...).fileMode())
 	if err != nil {
 		return fmt.Errorf("failed to write output file: %w", err)
 	}
@@ -97,10 +107,56 @@ func Exec(pathInput, pathOutput string) error {
 }
 
 // ExtractTestFunctions extracts test functions from source lines using AST information.
-func ExtractTestFunctions(lines []string, file *ast.File, fset *token.FileSet) ([]TestFunction, []string) {
-	testFuncPos := buildTestFunctionPositions(file, fset)
+func ExtractTestFunctions(
+	lines []string,
+	file *ast.File,
+	fset *token.FileSet,
+	opts ...Option,
+) ([]TestFunction, []string) {
+	options := newOptions(opts...)
+
+	if options.sortsDecls() {
+		lines = sortSpecLines(lines, file, fset, options)
+	}
 
-	return separateTestAndNonTestContent(lines, testFuncPos)
+	testFuncPos := buildTestFunctionPositions(file, fset, options)
+	commentStarts := buildCommentStarts(file, fset, options)
+	if options.Banners {
+		commentStarts = detachBanners(lines, commentStarts, options)
+	}
+
+	commentEnds := buildCommentEnds(file, fset, options)
+	testFuncs, nonTestLines := separateTestAndNonTestContent(lines, testFuncPos, commentStarts, commentEnds, options)
+
+	suites := buildSuiteNames(file, fset, testFuncPos)
+	strictKinds := buildStrictKinds(file, fset, testFuncPos, options)
+	types := buildTypeNames(file, fset, testFuncPos, options)
+	genDecls := buildGenDecls(file, fset, options)
+	commentedDecls := buildCommentedDecls(file, fset, options)
+
+	for i := range testFuncs {
+		identity := testFuncs[i].identity()
+
+		testFuncs[i].Suite = suites[identity]
+		testFuncs[i].Type = types[identity]
+
+		if kind, ok := strictKinds[identity]; ok {
+			testFuncs[i].Kind = kind
+		}
+
+		if genDecl, ok := genDecls[identity]; ok {
+			testFuncs[i].Name = firstSpecName(genDecl)
+			testFuncs[i].Token = genDecl.Tok
+		} else if fn, ok := commentedDecls[identity]; ok {
+			testFuncs[i].Receiver, testFuncs[i].Name = splitIdentity(funcIdentity(fn))
+			testFuncs[i].Kind = options.kindOf(fn.Name.Name)
+		} else {
+			// Functions declared several times, such as init, are keyed by line
+			testFuncs[i].Name, _, _ = strings.Cut(testFuncs[i].Name, " ")
+		}
+	}
+
+	return testFuncs, nonTestLines
 }
 
 // ParseGoFile reads and parses a Go source file, returning lines, AST, and FileSet.
@@ -111,361 +167,474 @@ func ParseGoFile(filePath string) ([]string, *ast.File, *token.FileSet, error) {
 		return nil, nil, nil, fmt.Errorf("failed to read input file: %w", err)
 	}
 
-	// Parse the file to get AST information
+	return ParseSource(filePath, content)
+}
+
+// ParseSource parses an in-memory Go source, returning lines, AST, and FileSet.
+// The filename is only used for position information and error messages. The
+// lines are split at "\n" only: sources with "\r\n" line endings or a byte
+// order mark are not supported by the line-based functions, use ReorderSource.
+func ParseSource(filename string, src []byte) ([]string, *ast.File, *token.FileSet, error) {
+	// Parse the source to get AST information
 	fset := token.NewFileSet()
 
-	file, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to parse Go file: %w", err)
 	}
 
 	// Split content into lines
-	lines := strings.Split(string(content), "\n")
+	lines := strings.Split(string(src), "\n")
 
 	return lines, file, fset, nil
 }
 
-// ============================================================================
-//  Private Functions (ABC Order)
-// ============================================================================
-
-// buildTestFunctionPositions creates a map of test function positions from AST.
-func buildTestFunctionPositions(file *ast.File, fset *token.FileSet) map[string][2]int {
-	testFuncPos := make(map[string][2]int) // name -> [start_line, end_line]
+// Reorder reads a Go source from src, reorders its test functions and writes
+// the result to dst. The filename is only used for error messages.
+func Reorder(filename string, src io.Reader, dst io.Writer, opts ...Option) error {
+	content, err := io.ReadAll(src)
+	if err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
 
-	for _, decl := range file.Decls {
-		function, ok := decl.(*ast.FuncDecl)
-		if !ok || !strings.HasPrefix(function.Name.Name, "Test") {
-			continue
-		}
+	output, err := ReorderSource(filename, content, opts...)
+	if err != nil {
+		return err // Error already includes proper context from ReorderSource
+	}
 
-		start := fset.Position(function.Pos()).Line
-		end := fset.Position(function.End()).Line
-		testFuncPos[function.Name.Name] = [2]int{start, end}
+	_, err = dst.Write(output)
+	if err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
 	}
 
-	return testFuncPos
+	return nil
 }
 
-// extractTestFunctionWithComments extracts a test function including its preceding comments.
-func extractTestFunctionWithComments(
-	lines []string,
-	funcName string,
-	testFuncPos map[string][2]int,
-) (TestFunction, int) {
-	pos := testFuncPos[funcName]
-	startLine := pos[0] - 1 // Convert to 0-based
-	endLine := pos[1] - 1   // Convert to 0-based
+// ReorderSource reorders test functions of an in-memory Go source and returns
+// the result. The filename is only used for error messages. The source is
+// never read from nor written to the filesystem. The UTF-8 byte order mark,
+// the "\r\n" line endings and the lack of a final newline of the source are
+// preserved. With Options.Gofmt, the result is formatted like gofmt does and a
+// *FormatError is returned if it cannot be, such as when a banner template
+// leaves a comment unterminated. An error wrapping ErrInvalidOption
+// is returned if the Options.BannerTemplate cannot be told apart from code.
+//
+// The result is verified to preserve the content of the source: the same
+// declarations, byte-identical function bodies and the same non-blank lines.
+// Otherwise, an error wrapping ErrContentMismatch is returned, so that Exec
+// never writes a damaged file. With Options.CheckEquivalence, the doc comments
+// are also verified to stay with their declaration, see Equivalent, or an
+// error wrapping ErrNotEquivalent is returned.
+func ReorderSource(filename string, src []byte, opts ...Option) ([]byte, error) {
+	options := newOptions(opts...)
+
+	err := checkBannerTemplate(options)
+	if err != nil {
+		return nil, err
+	}
 
-	commentStart := findCommentStart(lines, startLine)
+	style := detectStyle(src)
+	normalized := style.normalize(src)
 
-	var funcLines []string
-	for i := commentStart; i <= endLine; i++ {
-		funcLines = append(funcLines, lines[i])
+	// Parse the Go source
+	lines, file, fset, err := ParseSource(filename, normalized)
+	if err != nil {
+		return nil, err // Error already includes proper context from ParseSource
 	}
 
-	return TestFunction{
-		Name:  funcName,
-		Lines: funcLines,
-	}, endLine
-}
+	// Extract test functions and non-test content
+	testFuncs, nonTestLines := ExtractTestFunctions(lines, file, fset, opts...)
 
-// findTestFunctionAtLine checks if a specific line starts a test function.
-func findTestFunctionAtLine(lineNumber int, testFuncPos map[string][2]int) string {
-	for name, pos := range testFuncPos {
-		if lineNumber == pos[0] {
-			return name
+	// Build output content
+	output := []byte(BuildOutputContent(testFuncs, nonTestLines, opts...))
+
+	// Format first so that an output gofmt rejects is reported as such
+	formatted := output
+	if options.Gofmt {
+		formatted, err = formatOutput(filename, output)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	return ""
-}
-
-// findCommentStart finds the start of comments preceding a function.
-func findCommentStart(lines []string, functionStartLine int) int {
-	commentStart := functionStartLine
+	// Refuse any output that lost or duplicated content
+	err = verifyOutput(filename, normalized, output, options)
+	if err != nil {
+		return nil, err
+	}
 
-	// Find the start of comments and empty lines (mixed) preceding the function
-	for commentStart > 0 {
-		prevLine := strings.TrimSpace(lines[commentStart-1])
-		if strings.HasPrefix(prevLine, "//") || strings.HasPrefix(prevLine, "/*") || prevLine == "" {
-			commentStart--
-		} else {
-			break
+	if options.CheckEquivalence {
+		err = verifyEquivalence(filename, normalized, output)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	return commentStart
+	return style.restore(formatted), nil
 }
 
-// findCommentEnd finds the end of content following a function (including trailing empty lines).
-func findCommentEnd(lines []string, functionEndLine int) int {
-	commentEnd := functionEndLine
+// ============================================================================
+//  Private Functions (ABC Order)
+// ============================================================================
 
-	// Include trailing empty lines after the function
-	for commentEnd < len(lines)-1 {
-		nextLine := strings.TrimSpace(lines[commentEnd+1])
-		if nextLine == "" {
-			commentEnd++
-		} else {
-			break
-		}
+// identity returns the identity of the function: its name, or "Receiver.Name"
+// for methods.
+func (f TestFunction) identity() string {
+	if f.Receiver == "" {
+		return f.Name
 	}
 
-	return commentEnd
+	return f.Receiver + "." + f.Name
 }
 
-// isCommentBeforeTestFunction checks if a line is a comment preceding a test function.
-func isCommentBeforeTestFunction(lineIndex int, lines []string, testFuncStartLine int) bool {
-	// The testFuncStartLine parameter is expected to be 1-based (AST positions).
-	// Convert to 0-based index for comparing with lineIndex.
-	if testFuncStartLine <= 0 {
-		return false
+// appendBlankLines appends n empty lines to the output lines.
+func appendBlankLines(outputLines []string, n int) []string {
+	for range n {
+		outputLines = append(outputLines, "")
 	}
 
-	testStartIdx := testFuncStartLine - 1
+	return outputLines
+}
 
-	if lineIndex >= testStartIdx {
-		return false
+// appendTestFunction appends a test function to the output lines, separated from
+// the preceding content by the given number of blank lines.
+func appendTestFunction(outputLines []string, testFunc TestFunction, blankLines int) []string {
+	outputLines = trimTrailingBlankLines(outputLines)
+	if len(outputLines) > 0 {
+		outputLines = appendBlankLines(outputLines, blankLines)
 	}
 
-	trimmed := strings.TrimSpace(lines[lineIndex])
-	if !isCommentOrEmpty(trimmed) {
-		return false
+	// Remove leading empty lines from function content
+	funcLines := testFunc.Lines
+	for len(funcLines) > 0 && strings.TrimSpace(funcLines[0]) == "" {
+		funcLines = funcLines[1:]
 	}
 
-	// Empty lines are never part of test functions
-	if trimmed == "" {
-		return false
-	}
+	return append(outputLines, funcLines...)
+}
 
-	// Check if this comment is part of the test function:
-	// 1. Comment directly precedes the test function (no empty line between)
-	if lineIndex+1 == testStartIdx {
-		return true
+// buildAfterMarker places the sorted test functions right after the marker
+// comment line. If the marker is not found, they are placed at the bottom.
+func buildAfterMarker(testFuncs []TestFunction, nonTestLines []string, options Options) []string {
+	if index := findMarker(nonTestLines, options); index >= 0 {
+		return buildAtSlots(testFuncs, nonTestLines, uniformSlots(len(testFuncs), index+1), options)
 	}
 
-	// 2. Comment is separated by empty lines but has no other code before it
-	// Check if there are only empty lines between this comment and the test function
-	for i := lineIndex + 1; i < testStartIdx; i++ {
-		if strings.TrimSpace(lines[i]) != "" {
-			return false
+	return buildAtBottom(testFuncs, nonTestLines, options)
+}
+
+// buildAtBottom places the sorted test functions after all non-test lines,
+// except the file trailer which stays at the end, see findTrailerStart.
+func buildAtBottom(testFuncs []TestFunction, nonTestLines []string, options Options) []string {
+	trailer := findTrailerStart(testFuncs, nonTestLines)
+
+	sortTestFunctions(testFuncs, options)
+
+	outputLines := append([]string{}, nonTestLines[:trailer]...)
+
+	for i, testFunc := range testFuncs {
+		// A single blank line separates the non-test lines from the first function
+		blankLines := 1
+		if i > 0 {
+			blankLines = options.blankLines()
 		}
+
+		outputLines = appendBanner(outputLines, testFuncs, i, blankLines, options)
+		outputLines = appendTestFunction(outputLines, testFunc, blankLines)
 	}
 
-	// Check if there's any actual code (not package/import) before this comment
-	// Scan backwards to find any non-boilerplate code
-	for idx := lineIndex - 1; idx >= 0; idx-- {
-		if hasActualCodeAt(lines, idx) {
-			return false // Found actual code before this comment
-		}
+	if trailer < len(nonTestLines) && len(testFuncs) > 0 {
+		outputLines = appendBlankLines(trimTrailingBlankLines(outputLines), options.blankLines())
 	}
 
-	// No other code found before this comment, so it belongs to the test function
-	return true
+	return append(outputLines, nonTestLines[trailer:]...)
 }
 
-// hasActualCodeAt checks if there is actual (non-boilerplate) code at the given line index.
-func hasActualCodeAt(lines []string, idx int) bool {
-	line := strings.TrimSpace(lines[idx])
+// buildAtSlots sorts the test functions and places them at the given slots,
+// which are indexes in the non-test lines in ascending order.
+func buildAtSlots(testFuncs []TestFunction, nonTestLines []string, slots []int, options Options) []string {
+	sortTestFunctions(testFuncs, options)
 
-	if isBoilerplateCode(line) {
-		return false
-	}
+	var outputLines []string
 
-	return handleSpecialCases(lines, idx, line)
-}
+	next := 0
+	separate := false
 
-// isBoilerplateCode checks if a line is boilerplate code that should be ignored.
-func isBoilerplateCode(line string) bool {
-	switch {
-	case line == "":
-		return true // Skip empty lines
-	case strings.HasPrefix(line, "//") || strings.HasPrefix(line, "/*"):
-		return true // Skip other comments
-	case strings.HasPrefix(line, "package "):
-		return true // Package declarations don't count as "other code"
-	case strings.HasPrefix(line, "import "):
-		return true // Single-line imports don't count as "other code"
-	case line == importBlockStart:
-		return true // Skip entire import block
-	default:
-		return false
-	}
-}
-
-// handleSpecialCases handles special cases for import blocks and quoted content.
-func handleSpecialCases(lines []string, idx int, line string) bool {
-	switch {
-	case line == ")":
-		// This might be end of import block, skip back to find start
-		if isEndOfImportBlock(lines, idx) {
-			return false
+	for index, line := range nonTestLines {
+		for next < len(testFuncs) && slots[next] <= index {
+			outputLines = appendBanner(outputLines, testFuncs, next, options.blankLines(), options)
+			outputLines = appendTestFunction(outputLines, testFuncs[next], options.blankLines())
+			next++
+			separate = true
 		}
 
-		return true // Found actual code
-	case strings.Contains(line, "\""):
-		// Check if this line contains import content
-		if isInImportBlock(lines, idx) {
-			return false // Skip import content
+		if separate {
+			// Skip blank lines following a function and separate the next content
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+
+			outputLines = appendBlankLines(outputLines, options.blankLines())
+			separate = false
 		}
 
-		return true // Found actual code
-	default:
-		return true // Found actual code before this comment
+		outputLines = append(outputLines, line)
+	}
+
+	// Functions found after the last non-test line
+	for ; next < len(testFuncs); next++ {
+		outputLines = appendBanner(outputLines, testFuncs, next, options.blankLines(), options)
+		outputLines = appendTestFunction(outputLines, testFuncs[next], options.blankLines())
 	}
+
+	return outputLines
 }
 
-// isEndOfImportBlock checks if a closing parenthesis is the end of an import block.
-func isEndOfImportBlock(lines []string, idx int) bool {
-	for innerIdx := idx - 1; innerIdx >= 0; innerIdx-- {
-		prevLine := strings.TrimSpace(lines[innerIdx])
+// buildAtTop places the sorted test functions right after the import
+// declarations, or after the package clause if there are no imports.
+func buildAtTop(testFuncs []TestFunction, nonTestLines []string, options Options) []string {
+	slot := findImportsEnd(nonTestLines) + 1
 
-		switch {
-		case prevLine == "" || strings.HasPrefix(prevLine, "//"):
-			continue
-		case strings.Contains(prevLine, "\""):
-			continue // Import content
-		case prevLine == importBlockStart:
-			return true
-		case strings.HasPrefix(prevLine, "package "):
-			return false
-		default:
-			return false
-		}
+	return buildAtSlots(testFuncs, nonTestLines, uniformSlots(len(testFuncs), slot), options)
+}
+
+// buildInPlace places the sorted test functions into the slots originally
+// occupied by test functions, leaving the non-test lines where they were.
+func buildInPlace(testFuncs []TestFunction, nonTestLines []string, options Options) []string {
+	// Collect the original slots before sorting
+	slots := make([]int, len(testFuncs))
+	for i, testFunc := range testFuncs {
+		slots[i] = testFunc.Offset
 	}
 
-	return false
+	sort.Ints(slots)
+
+	return buildAtSlots(testFuncs, nonTestLines, slots, options)
 }
 
-// isInImportBlock checks if a line with quotes is inside an import block.
-func isInImportBlock(lines []string, idx int) bool {
-	for innerIdx := idx - 1; innerIdx >= 0; innerIdx-- {
-		prevLine := strings.TrimSpace(lines[innerIdx])
+// buildTestFunctionPositions creates a map of test function positions from AST.
+// The positions include the trailing comment following the closing brace, such
+// as } /* end of TestFoo */, even if it spans several lines.
+// Methods are keyed by "Receiver.Name" so that equally named methods of
+// different receivers never collide, and the functions that may be declared
+// several times, such as init, by their line too, see funcKey. With ModeTypes and SortDecls, the general
+// declarations are keyed by their identity, see genDeclIdentity. With
+// CommentedSort, the commented-out functions are keyed by their identity too,
+// see commentedFunc.identity.
+func buildTestFunctionPositions(file *ast.File, fset *token.FileSet, options Options) map[string][2]int {
+	testFuncPos := make(map[string][2]int) // identity -> [start_line, end_line]
+	testingName := testingImportName(file)
 
-		switch {
-		case prevLine == "" || strings.HasPrefix(prevLine, "//"):
-			continue
-		case strings.Contains(prevLine, "\""):
-			continue // Other import content
-		case prevLine == importBlockStart:
-			return true
-		case strings.HasPrefix(prevLine, "package "):
-			return false
+	for _, decl := range file.Decls {
+		var identity string
+
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if !options.match(decl, testingName) {
+				continue
+			}
+
+			identity = funcKey(decl, fset)
+		case *ast.GenDecl:
+			identity = genDeclIdentity(decl, fset, options)
+			if identity == "" {
+				continue
+			}
 		default:
-			return false
+			continue
 		}
+
+		start := fset.Position(decl.Pos()).Line
+		end := endLineWithComments(file, fset, fset.Position(decl.End()).Line)
+		testFuncPos[identity] = [2]int{start, end}
 	}
 
-	return false
-}
+	for _, commented := range findCommentedFuncs(file, fset, options) {
+		if options.Commented == CommentedSort && options.match(commented.fn, testingName) {
+			start := fset.Position(commented.group.Pos()).Line
+			end := fset.Position(commented.group.End()).Line
+			testFuncPos[commented.identity(fset)] = [2]int{start, end}
+		}
+	}
 
-// isCommentOrEmpty checks if a line is a comment or empty.
-func isCommentOrEmpty(line string) bool {
-	return line == "" || strings.HasPrefix(line, "//") || strings.HasPrefix(line, "/*")
+	return testFuncPos
 }
 
-// isLinePartOfTestFunction checks if a line is part of any test function.
-func isLinePartOfTestFunction(lineIndex int, lines []string, testFuncPos map[string][2]int) bool {
-	for _, pos := range testFuncPos {
-		// pos contains 1-based start and end line numbers (from token.FileSet).
-		startLine := pos[0]
-		endLine := pos[1]
+// closeGaps separates the non-test lines around each removed test function by
+// blank lines, since the blank lines surrounding a function are removed along
+// with it. The offsets of the test functions are updated to the output lines.
+func closeGaps(testFuncs []TestFunction, nonTestLines []string, options Options) []string {
+	gaps := make(map[int]bool, len(testFuncs))
+	for _, testFunc := range testFuncs {
+		gaps[testFunc.Offset] = true
+	}
+
+	outputLines := make([]string, 0, len(nonTestLines))
+	offsets := make(map[int]int, len(nonTestLines)+1)
 
-		// Convert to 0-based indices for comparison with lineIndex
-		startIdx := startLine - 1
-		endIdx := endLine - 1
+	for index, line := range nonTestLines {
+		offsets[index] = len(outputLines)
 
-		// Check if line is within test function range
-		if lineIndex >= startIdx && lineIndex <= endIdx {
-			return true
+		if gaps[index] && len(outputLines) > 0 &&
+			strings.TrimSpace(outputLines[len(outputLines)-1]) != "" && strings.TrimSpace(line) != "" {
+			outputLines = appendBlankLines(outputLines, options.blankLines())
 		}
 
-		// Check if this is an empty line that precedes a comment before test function
-		if isEmptyLinePrecedingComment(lineIndex, lines, startIdx) {
-			return true
+		outputLines = append(outputLines, line)
+	}
+
+	offsets[len(nonTestLines)] = len(outputLines)
+
+	for i := range testFuncs {
+		if offset, ok := offsets[testFuncs[i].Offset]; ok {
+			testFuncs[i].Offset = offset
 		}
 	}
 
-	return false
+	return outputLines
 }
 
-// isEmptyLinePrecedingComment checks if an empty line precedes a comment before a test function.
-func isEmptyLinePrecedingComment(lineIndex int, lines []string, startIdx int) bool {
-	// Line must be before the test function
-	if lineIndex >= startIdx {
-		return false
+// countUnprocessedLines counts the lines before the given index that are not
+// part of any test function.
+func countUnprocessedLines(processedLines map[int]bool, before int) int {
+	count := 0
+
+	for i := range before {
+		if !processedLines[i] {
+			count++
+		}
 	}
 
-	// Line must be empty
-	line := strings.TrimSpace(lines[lineIndex])
-	if line != "" {
-		return false
+	return count
+}
+
+// extractTestFunctionWithComments extracts a test function including its preceding comments.
+func extractTestFunctionWithComments(lines []string, funcInfo funcPos, options Options) TestFunction {
+	commentStart := findOwnedCommentStart(lines, funcInfo, options)
+
+	var funcLines []string
+	for i := commentStart; i <= funcInfo.endLine; i++ {
+		funcLines = append(funcLines, lines[i])
 	}
 
-	// Check if there's a comment between this empty line and the test function
-	return hasCommentBeforeTest(lineIndex, lines, startIdx)
+	receiver, name := splitIdentity(funcInfo.name)
+
+	return TestFunction{
+		Name:     name,
+		Lines:    funcLines,
+		Kind:     options.kindOf(name),
+		Receiver: receiver,
+	}
 }
 
-// hasCommentBeforeTest checks if there's a comment between an empty line and a test function.
-func hasCommentBeforeTest(lineIndex int, lines []string, startIdx int) bool {
-	for forwardIndex := lineIndex + 1; forwardIndex < startIdx; forwardIndex++ {
-		nextLine := strings.TrimSpace(lines[forwardIndex])
+// findCommentEnd finds the end of content following a function (including trailing empty lines).
+func findCommentEnd(lines []string, functionEndLine int) int {
+	commentEnd := functionEndLine
+
+	// Include trailing empty lines after the function
+	for commentEnd < len(lines)-1 {
+		nextLine := strings.TrimSpace(lines[commentEnd+1])
 		if nextLine == "" {
-			continue // Skip empty lines
+			commentEnd++
+		} else {
+			break
 		}
+	}
 
-		if !strings.HasPrefix(nextLine, "//") && !strings.HasPrefix(nextLine, "/*") {
-			break // Found non-comment content
-		}
+	return commentEnd
+}
 
-		// Found comment, check if it's the last non-empty content before test function
-		if isLastContentBeforeTest(forwardIndex, lines, startIdx) {
-			return true // Empty line precedes comment before test
-		}
+// findImportsEnd returns the index of the last line of the import declarations,
+// or of the package clause if there are no imports. It returns -1 if the lines
+// do not parse.
+func findImportsEnd(lines []string) int {
+	fset := token.NewFileSet()
 
-		break // Found non-comment content
+	file, err := parser.ParseFile(fset, "", strings.Join(lines, "\n"), parser.ImportsOnly)
+	if err != nil {
+		return -1
 	}
 
-	return false
+	end := file.Name.End()
+
+	for _, decl := range file.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT {
+			end = genDecl.End()
+		}
+	}
+
+	return fset.Position(end).Line - 1 // Convert to 0-based
 }
 
-// isLastContentBeforeTest checks if a comment is the last content before a test function.
-func isLastContentBeforeTest(commentIndex int, lines []string, startIdx int) bool {
-	for innerIdx := commentIndex + 1; innerIdx < startIdx; innerIdx++ {
-		if strings.TrimSpace(lines[innerIdx]) != "" {
-			return false
+// findOwnedCommentStart finds the start of the comments preceding a function
+// that move along with it. The placement marker comment never moves, so the
+// comments owned by the function start after it. The section banners are
+// detached by detachBanners beforehand.
+func findOwnedCommentStart(lines []string, funcInfo funcPos, options Options) int {
+	commentStart := funcInfo.commentStart
+	functionStartLine := funcInfo.startLine
+
+	for i := functionStartLine - 1; i >= commentStart; i-- {
+		if options.isMarker(lines[i]) {
+			return i + 1
 		}
 	}
 
-	return true
+	return commentStart
 }
 
-// funcPos represents the position of a function in the source code.
+// funcPos represents the position of a function in the source code. The lines
+// are 0-based and commentStart is the first line of the comments and blank
+// lines preceding the function.
 type funcPos struct {
-	name      string
-	startLine int
-	endLine   int
+	name         string
+	commentStart int
+	startLine    int
+	endLine      int
 }
 
 // separateTestAndNonTestContent processes lines to separate test functions from other content.
-func separateTestAndNonTestContent(lines []string, testFuncPos map[string][2]int) ([]TestFunction, []string) {
-	sortedFuncs := createSortedFuncPositions(testFuncPos)
-	processedLines := markProcessedLines(lines, sortedFuncs)
-	testFuncs := extractAllTestFunctions(lines, sortedFuncs, testFuncPos)
+func separateTestAndNonTestContent(
+	lines []string,
+	testFuncPos map[string][2]int,
+	commentStarts map[int]int,
+	commentEnds map[int]int,
+	options Options,
+) ([]TestFunction, []string) {
+	sortedFuncs := createSortedFuncPositions(testFuncPos, commentStarts, commentEnds)
+	processedLines := markProcessedLines(lines, sortedFuncs, options)
+	testFuncs := extractAllTestFunctions(lines, sortedFuncs, processedLines, options)
 	nonTestLines := collectNonTestLines(lines, processedLines)
 
 	return testFuncs, nonTestLines
 }
 
 // createSortedFuncPositions creates a sorted list of test function positions.
-func createSortedFuncPositions(testFuncPos map[string][2]int) []funcPos {
+// The comment starts map the 0-based start lines of the declarations to the
+// first line of their preceding comments, see buildCommentStarts. Functions
+// missing from it have no preceding comments. The comment ends map the 0-based
+// end lines of the declarations to the last line of the floating comments
+// following them, see buildCommentEnds.
+func createSortedFuncPositions(testFuncPos map[string][2]int, commentStarts, commentEnds map[int]int) []funcPos {
 	sortedFuncs := make([]funcPos, 0, len(testFuncPos))
 	for name, pos := range testFuncPos {
+		startLine := pos[0] - 1 // Convert to 0-based
+
+		commentStart, ok := commentStarts[startLine]
+		if !ok {
+			commentStart = startLine
+		}
+
+		endLine := pos[1] - 1 // Convert to 0-based
+		if commentEnd, ok := commentEnds[endLine]; ok {
+			endLine = commentEnd
+		}
+
 		sortedFuncs = append(sortedFuncs, funcPos{
-			name:      name,
-			startLine: pos[0] - 1, // Convert to 0-based
-			endLine:   pos[1] - 1, // Convert to 0-based
+			name:         name,
+			commentStart: commentStart,
+			startLine:    startLine,
+			endLine:      endLine,
 		})
 	}
 
@@ -482,7 +651,7 @@ func createSortedFuncPositions(testFuncPos map[string][2]int) []funcPos {
 }
 
 // markProcessedLines marks all lines that are part of test functions and their comments.
-func markProcessedLines(lines []string, sortedFuncs []funcPos) map[int]bool {
+func markProcessedLines(lines []string, sortedFuncs []funcPos, options Options) map[int]bool {
 	processedLines := make(map[int]bool)
 
 	for _, funcInfo := range sortedFuncs {
@@ -491,7 +660,7 @@ func markProcessedLines(lines []string, sortedFuncs []funcPos) map[int]bool {
 
 		// Ensure we don't go out of bounds
 		if startLine >= 0 && startLine < len(lines) && endLine >= 0 && endLine < len(lines) {
-			commentStart := findCommentStart(lines, startLine)
+			commentStart := findOwnedCommentStart(lines, funcInfo, options)
 			commentEnd := findCommentEnd(lines, endLine)
 
 			// Mark all lines from comment start to comment end as processed
@@ -505,13 +674,19 @@ func markProcessedLines(lines []string, sortedFuncs []funcPos) map[int]bool {
 }
 
 // extractAllTestFunctions extracts all test functions using the sorted positions.
-func extractAllTestFunctions(lines []string, sortedFuncs []funcPos, testFuncPos map[string][2]int) []TestFunction {
+func extractAllTestFunctions(
+	lines []string,
+	sortedFuncs []funcPos,
+	processedLines map[int]bool,
+	options Options,
+) []TestFunction {
 	var testFuncs []TestFunction
 
 	for _, funcInfo := range sortedFuncs {
 		startLine := funcInfo.startLine
 		if startLine >= 0 && startLine < len(lines) {
-			testFunc, _ := extractTestFunctionWithComments(lines, funcInfo.name, testFuncPos)
+			testFunc := extractTestFunctionWithComments(lines, funcInfo, options)
+			testFunc.Offset = countUnprocessedLines(processedLines, findOwnedCommentStart(lines, funcInfo, options))
 			testFuncs = append(testFuncs, testFunc)
 		}
 	}
@@ -536,3 +711,64 @@ func collectNonTestLines(lines []string, processedLines map[int]bool) []string {
 
 	return nonTestLines
 }
+
+// sortTestFunctions groups the test functions by kind, or with ModeFuncs by
+// export status, the exported methods apart, see Options.groupRank, and sorts
+// each group by name using the configured comparison, keeping the original
+// order of equal keys. Methods with the same name are ordered by receiver type.
+// TestMain comes first with TestMainFirst. With ModeTypes, each type is
+// followed by its constructors and methods. With SortDecls, the types,
+// constants and variables come first.
+func sortTestFunctions(testFuncs []TestFunction, options Options) {
+	runners := suiteRunners(testFuncs)
+
+	sortGroup := func(testFunc TestFunction) (string, int) {
+		switch {
+		case options.Mode == ModeTypes:
+			return typeGroup(testFunc)
+		case options.GroupSuites:
+			return suiteGroup(testFunc, runners)
+		default:
+			return testFunc.Name, 0
+		}
+	}
+
+	sort.SliceStable(testFuncs, func(i, j int) bool {
+		// Declarations of a class kept in their original order
+		declRank := cmp.Compare(options.declRank(testFuncs[i]), options.declRank(testFuncs[j]))
+		if declRank != 0 || options.keepsDeclOrder(testFuncs[i]) {
+			return declRank < 0
+		}
+
+		groupI, rankI := sortGroup(testFuncs[i])
+		groupJ, rankJ := sortGroup(testFuncs[j])
+
+		return cmp.Or(
+			cmp.Compare(options.groupRank(testFuncs[i]), options.groupRank(testFuncs[j])),
+			cmp.Compare(options.firstRank(testFuncs[i]), options.firstRank(testFuncs[j])),
+			options.compare(groupI, groupJ),
+			cmp.Compare(rankI, rankJ),
+			options.compare(testFuncs[i].Name, testFuncs[j].Name),
+			strings.Compare(testFuncs[i].Receiver, testFuncs[j].Receiver),
+		) < 0
+	})
+}
+
+// uniformSlots returns count slots that all point to the same index.
+func uniformSlots(count, index int) []int {
+	slots := make([]int, count)
+	for i := range slots {
+		slots[i] = index
+	}
+
+	return slots
+}
+
+// trimTrailingBlankLines removes the empty lines at the end of the output lines.
+func trimTrailingBlankLines(outputLines []string) []string {
+	for len(outputLines) > 0 && strings.TrimSpace(outputLines[len(outputLines)-1]) == "" {
+		outputLines = outputLines[:len(outputLines)-1]
+	}
+
+	return outputLines
+}
@@ -1,13 +1,17 @@
 package reorderfuncs
 
 import (
+	"bytes"
+	"errors"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -23,6 +27,34 @@ type TestFunc struct {
 	Pos  token.Pos
 }
 
+// failingWriter is an io.Writer that always fails with the given error.
+type failingWriter struct {
+	err error
+}
+
+// Write implements io.Writer.
+func (w failingWriter) Write([]byte) (int, error) {
+	return 0, w.err
+}
+
+// funcNames returns the names of the function declarations in the source, in order.
+func funcNames(t *testing.T, src []byte) []string {
+	t.Helper()
+
+	file, err := parser.ParseFile(token.NewFileSet(), "src.go", src, 0)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(file.Decls))
+
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			names = append(names, fn.Name.Name)
+		}
+	}
+
+	return names
+}
+
 // parseGoFileTestCase represents a test case for ParseGoFile.
 type parseGoFileTestCase struct {
 	name          string
@@ -54,14 +86,14 @@ func extractTestFunctions(node *ast.File) []TestFunc {
 // runEdgeCaseTest executes a single edge case test for the Exec function.
 //
 //nolint:gosec // File path is controlled in test environment
-func runEdgeCaseTest(t *testing.T, beforeFile, expectFile string) {
+func runEdgeCaseTest(t *testing.T, beforeFile, expectFile string, opts ...Option) {
 	t.Helper()
 
 	// Create temp output file
 	outputPath := filepath.Join(t.TempDir(), "output.go")
 
 	// Execute the reordering
-	err := Exec(beforeFile, outputPath)
+	err := Exec(beforeFile, outputPath, opts...)
 	require.NoError(t, err)
 
 	// Read actual output
@@ -165,6 +197,254 @@ func runParseGoFileTest(t *testing.T, test parseGoFileTestCase) {
 //	Public Functions (ABC Order)
 // ============================================================================
 
+func TestBuildOutputContent_after_marker(t *testing.T) {
+	t.Parallel()
+
+	source := `package main
+
+import "testing"
+
+func Test_bob(t *testing.T) {}
+
+// Tests
+
+func Test_alice(t *testing.T) {}
+
+func helper() {}
+`
+
+	tests := []struct {
+		name   string
+		marker string
+		expect string
+	}{
+		{
+			name:   "marker found",
+			marker: "// Tests",
+			expect: `package main
+
+import "testing"
+
+// Tests
+
+func Test_alice(t *testing.T) {}
+
+func Test_bob(t *testing.T) {}
+
+func helper() {}
+`,
+		},
+		{
+			name:   "marker not found falls back to bottom",
+			marker: "// Missing",
+			expect: `package main
+
+import "testing"
+
+func helper() {}
+
+// Tests
+
+func Test_alice(t *testing.T) {}
+
+func Test_bob(t *testing.T) {}
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := ReorderSource("marker_test.go", []byte(source), WithMarker(test.marker))
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expect, string(actual))
+		})
+	}
+
+	// The indented marker within the helper body is not the anchor
+	indented := "package main\n\nfunc helper() {\n\t// Tests\n}\n\nfunc TestB() {}\n\nfunc TestA() {}\n"
+
+	actual, err := ReorderSource("marker_test.go", []byte(indented), WithMarker("// Tests"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n\nfunc helper() {\n\t// Tests\n}\n\nfunc TestA() {}\n\nfunc TestB() {}\n",
+		string(actual))
+}
+
+func TestBuildOutputContent_in_place(t *testing.T) {
+	t.Parallel()
+
+	// Source: package, Test_charlie, helper, Test_alpha, Test_bob
+	testFuncs := []TestFunction{
+		{Name: "Test_charlie", Lines: []string{"", "func Test_charlie() {}"}, Offset: 2},
+		{Name: "Test_alpha", Lines: []string{"", "func Test_alpha() {}"}, Offset: 4},
+		{Name: "Test_bob", Lines: []string{"", "func Test_bob() {}"}, Offset: 4},
+	}
+	nonTestLines := []string{"package main", "", "func helper() {", "}", ""}
+
+	actual := BuildOutputContent(testFuncs, nonTestLines, WithPlacement(PlaceInPlace))
+
+	expect := strings.Join([]string{
+		"package main",
+		"",
+		"func Test_alpha() {}",
+		"",
+		"func helper() {",
+		"}",
+		"",
+		"func Test_bob() {}",
+		"",
+		"func Test_charlie() {}",
+	}, "\n") + "\n"
+	assert.Equal(t, expect, actual)
+}
+
+func TestBuildOutputContent_options(t *testing.T) {
+	t.Parallel()
+
+	testFuncs := []TestFunction{
+		{Name: "Test_alpha", Lines: []string{"func Test_alpha() {}"}},
+		{Name: "Test_beta", Lines: []string{"func Test_beta() {}"}},
+	}
+	nonTestLines := []string{"package main", ""}
+
+	reverse := func(a, b string) int { return strings.Compare(b, a) }
+
+	actual := BuildOutputContent(testFuncs, nonTestLines, WithCompare(reverse), WithBlankLines(2))
+
+	expect := "package main\n\nfunc Test_beta() {}\n\n\nfunc Test_alpha() {}\n"
+	assert.Equal(t, expect, actual)
+}
+
+func TestBuildOutputContent_top(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		source string
+		expect string
+	}{
+		{
+			name: "after import block",
+			source: `package main
+
+import (
+	"testing"
+)
+
+func helper() {}
+
+func Test_bob(t *testing.T) {}
+
+func Test_alice(t *testing.T) {}
+`,
+			expect: `package main
+
+import (
+	"testing"
+)
+
+func Test_alice(t *testing.T) {}
+
+func Test_bob(t *testing.T) {}
+
+func helper() {}
+`,
+		},
+		{
+			name: "after package clause without imports",
+			source: `package main
+
+var global = 1
+
+func Test_bob() {}
+
+func Test_alice() {}
+`,
+			expect: `package main
+
+func Test_alice() {}
+
+func Test_bob() {}
+
+var global = 1
+`,
+		},
+		{
+			name: "package doc with a declaration line",
+			source: `/*
+Package main does nothing.
+
+func main() {}
+*/
+package main
+
+import "testing"
+
+func Test_bob(t *testing.T) {}
+
+func helper() {}
+
+func Test_alice(t *testing.T) {}
+`,
+			expect: `/*
+Package main does nothing.
+
+func main() {}
+*/
+package main
+
+import "testing"
+
+func Test_alice(t *testing.T) {}
+
+func Test_bob(t *testing.T) {}
+
+func helper() {}
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := ReorderSource("top_test.go", []byte(test.source), WithPlacement(PlaceTop))
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expect, string(actual))
+		})
+	}
+}
+
+func TestBuildOutputContent_sort_key_is_stable(t *testing.T) {
+	t.Parallel()
+
+	testFuncs := []TestFunction{
+		{Name: "TestZeta", Lines: []string{"func TestZeta() {}"}},
+		{Name: "TestFoo_bar", Lines: []string{"func TestFoo_bar() {}"}},
+		{Name: "Test_alpha", Lines: []string{"func Test_alpha() {}"}},
+		{Name: "Test_foo_bar", Lines: []string{"func Test_foo_bar() {}"}},
+	}
+
+	actual := BuildOutputContent(testFuncs, nil,
+		WithSortKey(SortKeyFoldCase|SortKeyIgnoreUnderscore|SortKeyTrimPrefix))
+
+	// TestFoo_bar and Test_foo_bar have the same key and keep their original order
+	expect := strings.Join([]string{
+		"func Test_alpha() {}",
+		"",
+		"func TestFoo_bar() {}",
+		"",
+		"func Test_foo_bar() {}",
+		"",
+		"func TestZeta() {}",
+	}, "\n") + "\n"
+	assert.Equal(t, expect, actual)
+}
+
 func TestExec_argument_check(t *testing.T) {
 	t.Parallel()
 
@@ -218,6 +498,19 @@ func TestExec_argument_check(t *testing.T) {
 	}
 }
 
+func TestExec_file_mode(t *testing.T) {
+	t.Parallel()
+
+	pathOutput := filepath.Join(t.TempDir(), "output.go")
+
+	err := Exec("testdata/test_sample1_before", pathOutput, WithFileMode(0o600))
+	require.NoError(t, err)
+
+	info, err := os.Stat(pathOutput)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
 // TestExec_comprehensive_edge_cases tests the Exec function with various edge cases
 // including mixed content, generics, unicode characters, and complex test patterns.
 func TestExec_comprehensive_edge_cases(t *testing.T) {
@@ -227,12 +520,19 @@ func TestExec_comprehensive_edge_cases(t *testing.T) {
 		name       string
 		beforeFile string
 		expectFile string
+		opts       []Option
 	}{
 		{
 			name:       "basic_reordering",
 			beforeFile: "testdata/test_sample1_before",
 			expectFile: "testdata/test_sample1_expect",
 		},
+		{
+			name:       "in_place_keeps_helpers_between_tests",
+			beforeFile: "testdata/test_sample2_before",
+			expectFile: "testdata/test_sample2_expect_in_place",
+			opts:       []Option{WithPlacement(PlaceInPlace)},
+		},
 		/* NOTE: Re-enable when var/const/type block handling spec is finalized
 		{
 			name:       "mixed_content_with_structs_and_methods",
@@ -245,7 +545,7 @@ func TestExec_comprehensive_edge_cases(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			runEdgeCaseTest(t, tc.beforeFile, tc.expectFile)
+			runEdgeCaseTest(t, tc.beforeFile, tc.expectFile, tc.opts...)
 		})
 	}
 }
@@ -332,6 +632,35 @@ func Test_second(t *testing.T) {
 	}
 }
 
+func TestExtractTestFunctions_methods_do_not_collide(t *testing.T) {
+	t.Parallel()
+
+	source := `package main
+
+func (s *FooSuite) TestCreate() {
+	s.Equal("foo", "foo")
+}
+
+func (s *BarSuite) TestCreate() {
+	s.Equal("bar", "bar")
+}`
+
+	lines := strings.Split(source, "\n")
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", source, parser.ParseComments)
+	require.NoError(t, err)
+
+	testFuncs, _ := ExtractTestFunctions(lines, file, fset)
+
+	require.Len(t, testFuncs, 2, "methods with the same name should not collide")
+	assert.Equal(t, "TestCreate", testFuncs[0].Name)
+	assert.Equal(t, "FooSuite", testFuncs[0].Receiver)
+	assert.Contains(t, strings.Join(testFuncs[0].Lines, "\n"), `"foo"`)
+	assert.Equal(t, "TestCreate", testFuncs[1].Name)
+	assert.Equal(t, "BarSuite", testFuncs[1].Receiver)
+	assert.Contains(t, strings.Join(testFuncs[1].Lines, "\n"), `"bar"`)
+}
+
 func TestExtractTestFunctions_with_comments(t *testing.T) {
 	t.Parallel()
 
@@ -425,178 +754,994 @@ func Test_example(t *testing.T) {
 	}
 }
 
-// ============================================================================
-//  Private Functions (ABC Order)
-// ============================================================================
-
-func Test_buildTestFunctionPositions_golden(t *testing.T) {
+func TestParseSource_golden(t *testing.T) {
 	t.Parallel()
 
-	source := `package main
+	source := []byte(`package main
 
 import "testing"
 
-func Test_alpha(t *testing.T) {
-	// line 6
-}
-
-func regularFunc() {
-	// line 10
-}
+func Test_example(t *testing.T) {
+	// Test implementation
+}`)
 
-func Test_beta(t *testing.T) {
-	// line 14
-	// line 15
-}`
+	lines, file, fset, err := ParseSource("in_memory.go", source)
 
-	fset := token.NewFileSet()
-	file, err := parser.ParseFile(fset, "test.go", source, parser.ParseComments)
 	require.NoError(t, err)
+	assert.Len(t, lines, 7, "line count should match")
+	assert.Equal(t, "main", file.Name.Name, "package name should match")
+	assert.Equal(t, "in_memory.go", fset.Position(file.Package).Filename,
+		"filename should be used for position information")
+}
 
-	positions := buildTestFunctionPositions(file, fset)
+func TestParseSource_invalid_syntax(t *testing.T) {
+	t.Parallel()
 
-	expected := map[string][2]int{
-		"Test_alpha": {5, 7},   // Lines 5-7
-		"Test_beta":  {13, 16}, // Lines 13-16
-	}
+	_, _, _, err := ParseSource("invalid.go", []byte(`package invalid syntax`))
 
-	assert.Equal(t, expected, positions)
-	assert.NotContains(t, positions, "regularFunc", "regular functions should not be included")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse Go file")
+	assert.Contains(t, err.Error(), "invalid.go", "error should mention the filename")
 }
 
-func Test_extractTestFunctionWithComments_golden(t *testing.T) {
+func TestReorderSource_banners(t *testing.T) {
 	t.Parallel()
 
-	lines := []string{
-		"package main",                      // 0
-		"",                                  // 1
-		"import \"testing\"",                // 2
-		"",                                  // 3
-		"// Comment for test",               // 4
-		"// Another comment",                // 5
-		"func Test_example(t *testing.T) {", // 6
-		"	// Test body",                     // 7
-		"	pass := true",                     // 8
-		"}",                                 // 9
-		"",                                  // 10
-		"func regularFunc() {",              // 11
-		"}",                                 // 12
-	}
+	input, err := os.ReadFile("testdata/test_sample5_before")
+	require.NoError(t, err)
 
-	testFuncPos := map[string][2]int{
-		"Test_example": {7, 10}, // Lines 7-10 (1-based)
-	}
+	expect, err := os.ReadFile("testdata/test_sample5_expect_banners")
+	require.NoError(t, err)
 
-	testFunc, endIndex := extractTestFunctionWithComments(lines, "Test_example", testFuncPos)
+	opts := []Option{WithBanners(""), WithKinds(KindTest, KindBenchmark, KindHelper)}
 
-	assert.Equal(t, "Test_example", testFunc.Name)
-	assert.Equal(t, 9, endIndex) // 0-based end index
+	actual, err := ReorderSource("shapes_test.go", input, opts...)
+	require.NoError(t, err)
+	assert.Equal(t, string(expect), string(actual))
 
-	expectedLines := []string{
-		"", // Empty line before comments
-		"// Comment for test",
-		"// Another comment",
-		"func Test_example(t *testing.T) {",
-		"	// Test body",
-		"	pass := true",
-		"}",
-	}
-	assert.Equal(t, expectedLines, testFunc.Lines)
+	again, err := ReorderSource("shapes_test.go", actual, opts...)
+	require.NoError(t, err)
+	assert.Equal(t, string(actual), string(again), "regenerating the banners should be idempotent")
 }
 
-func Test_findCommentStart_golden(t *testing.T) {
+func TestReorderSource_banners_idempotent(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name                 string
-		lines                []string
-		functionStartLine    int
-		expectedCommentStart int
+		name     string
+		filename string
+		source   string
+		opts     []Option
 	}{
 		{
-			name: "function with preceding comments",
-			lines: []string{
-				"package main",                      // 0
-				"",                                  // 1
-				"// Comment 1",                      // 2
-				"// Comment 2",                      // 3
-				"func Test_example(t *testing.T) {", // 4
-				"}",                                 // 5
-			},
-			functionStartLine:    4, // 0-based index for "func Test_example"
-			expectedCommentStart: 1, // 0-based index for empty line before comments
-		},
-		{
-			name: "function with no preceding comments",
-			lines: []string{
-				"package main",                      // 0
-				"",                                  // 1
-				"func regularFunc() {",              // 2
-				"}",                                 // 3
-				"func Test_example(t *testing.T) {", // 4
-				"}",                                 // 5
-			},
-			functionStartLine:    4, // 0-based index for "func Test_example"
-			expectedCommentStart: 4, // Same as function start (no comments)
-		},
-		{
-			name: "function with mixed empty lines and comments",
-			lines: []string{
-				"package main",                      // 0
-				"",                                  // 1
-				"// Comment",                        // 2
-				"",                                  // 3
-				"// Another comment",                // 4
-				"func Test_example(t *testing.T) {", // 5
-				"}",                                 // 6
-			},
-			functionStartLine:    5, // 0-based index for "func Test_example"
-			expectedCommentStart: 1, // 0-based index for first empty line before comments
-		},
-	}
+			name:     "funcs mode sections",
+			filename: "shapes.go",
+			source: `package shapes
 
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			t.Parallel()
+// ============================================================================
+//  Private Functions (ABC Order)
+// ============================================================================
 
-			commentStart := findCommentStart(test.lines, test.functionStartLine)
-			assert.Equal(t, test.expectedCommentStart, commentStart)
-		})
-	}
-}
+func scale(value float64) float64 { return value }
 
-func Test_findTestFunctionAtLine_golden(t *testing.T) {
-	t.Parallel()
+// ============================================================================
+//  Methods (ABC Order)
+// ============================================================================
 
-	testFuncPos := map[string][2]int{
-		"Test_alpha": {5, 7},
-		"Test_beta":  {10, 12},
-	}
+// Area returns the area.
+func (c *Circle) Area() float64 { return scale(c.Radius) }
 
-	tests := []struct {
-		name         string
-		lineNumber   int
-		expectedFunc string
-	}{
-		{
-			name:         "line matches Test_alpha start",
-			lineNumber:   5,
-			expectedFunc: "Test_alpha",
-		},
-		{
-			name:         "line matches Test_beta start",
-			lineNumber:   10,
-			expectedFunc: "Test_beta",
+// ============================================================================
+//  Public Functions (ABC Order)
+// ============================================================================
+
+// NewCircle returns a circle.
+func NewCircle() *Circle { return &Circle{} }
+
+// Circle is a circle.
+type Circle struct{ Radius float64 }
+`,
+			opts: []Option{WithMode(ModeFuncs), WithBanners("")},
+		},
+		{
+			name:     "floating comment before the banner",
+			filename: "floating_test.go",
+			source: `package main
+
+import "testing"
+
+// Tests
+
+// ---- Methods ----
+
+func TestB(t *testing.T) {}
+
+func TestA(t *testing.T) {}
+`,
+			opts: []Option{WithBanners("// %s")},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			first, err := ReorderSource(test.filename, []byte(test.source), test.opts...)
+			require.NoError(t, err)
+
+			second, err := ReorderSource(test.filename, first, test.opts...)
+
+			require.NoError(t, err)
+			assert.Equal(t, string(first), string(second), "a second run should be a no-op")
+		})
+	}
+}
+
+func TestReorderSource_banners_keep_comments(t *testing.T) {
+	t.Parallel()
+
+	source := `package main
+
+import "testing"
+
+// helper builds the fixture.
+func helper() {}
+
+// TestB checks B.
+func TestB(t *testing.T) {}
+
+// TestA checks A.
+func TestA(t *testing.T) {}
+`
+	expect := `package main
+
+import "testing"
+
+// helper builds the fixture.
+func helper() {}
+
+// Tests
+
+// TestA checks A.
+func TestA(t *testing.T) {}
+
+// TestB checks B.
+func TestB(t *testing.T) {}
+`
+	actual, err := ReorderSource("comments_test.go", []byte(source), WithBanners("// %s"))
+
+	require.NoError(t, err)
+	assert.Equal(t, expect, string(actual), "doc comments matching the template are not banners")
+
+	again, err := ReorderSource("comments_test.go", actual, WithBanners("// %s"))
+
+	require.NoError(t, err)
+	assert.Equal(t, expect, string(again), "regenerating the banners should be idempotent")
+
+	_, err = ReorderSource("comments_test.go", []byte(source), WithBanners("\t%s"))
+
+	require.ErrorIs(t, err, ErrInvalidOption, "a bare title cannot be told apart from the code")
+
+	source = `package main
+
+import "testing"
+
+// Examples
+var registry = 1
+
+// Helpers
+
+func TestB(t *testing.T) {}
+
+func TestA(t *testing.T) {}
+`
+	expect = `package main
+
+import "testing"
+
+// Examples
+var registry = 1
+
+// Helpers
+
+// Tests
+
+func TestA(t *testing.T) {}
+
+func TestB(t *testing.T) {}
+`
+	actual, err = ReorderSource("comments_test.go", []byte(source), WithBanners("// %s"))
+
+	require.NoError(t, err)
+	assert.Equal(t, expect, string(actual), "doc comments and banners of other groups are kept")
+}
+
+func TestReorderSource_block_comments(t *testing.T) {
+	t.Parallel()
+
+	source := `package main
+
+import "testing"
+
+/*
+TestZulu checks the last case.
+	It is documented by a block comment.
+*/
+func TestZulu(t *testing.T) {
+	t.Log("// not a comment")
+}
+
+/* TestAlpha is documented
+   by a block comment too. */
+func TestAlpha(t *testing.T) {}
+`
+	expect := `package main
+
+import "testing"
+
+/* TestAlpha is documented
+   by a block comment too. */
+func TestAlpha(t *testing.T) {}
+
+/*
+TestZulu checks the last case.
+	It is documented by a block comment.
+*/
+func TestZulu(t *testing.T) {
+	t.Log("// not a comment")
+}
+`
+	actual, err := ReorderSource("block_test.go", []byte(source))
+
+	require.NoError(t, err)
+	assert.Equal(t, expect, string(actual))
+}
+
+func TestReorderSource_check_equivalence(t *testing.T) {
+	t.Parallel()
+
+	samples, err := filepath.Glob("testdata/test_sample*_before")
+	require.NoError(t, err)
+	require.NotEmpty(t, samples)
+
+	for _, sample := range samples {
+		input, err := os.ReadFile(sample)
+		require.NoError(t, err)
+
+		for _, opts := range [][]Option{
+			nil,
+			{WithPlacement(PlaceInPlace)},
+			{WithGroupSuites(), WithBanners("")},
+			{WithMode(ModeFuncs), WithFloating(FloatingPrevious)},
+			{WithMode(ModeTypes), WithSortDecls(), WithSpacing(SpacingNormalize)},
+		} {
+			_, err := ReorderSource(sample, input, append(opts, WithCheckEquivalence())...)
+
+			require.NoError(t, err, "every doc comment should stay with its declaration in %s", sample)
+		}
+	}
+}
+
+func TestReorderSource_commented(t *testing.T) {
+	t.Parallel()
+
+	source := `package main
+
+import "testing"
+
+func TestCharlie(t *testing.T) {}
+
+// func TestOld(t *testing.T) {
+// 	t.Skip("flaky")
+// }
+
+func TestBravo(t *testing.T) {
+	// func TestInner(t *testing.T) {}
+}
+
+func TestAlpha(t *testing.T) {}
+`
+	tests := []struct {
+		name   string
+		policy CommentedPolicy
+		expect string
+	}{
+		{
+			name:   "keep",
+			policy: CommentedKeep,
+			expect: `package main
+
+import "testing"
+
+// func TestOld(t *testing.T) {
+// 	t.Skip("flaky")
+// }
+
+func TestAlpha(t *testing.T) {}
+
+func TestBravo(t *testing.T) {
+	// func TestInner(t *testing.T) {}
+}
+
+func TestCharlie(t *testing.T) {}
+`,
+		},
+		{
+			name:   "sort",
+			policy: CommentedSort,
+			expect: `package main
+
+import "testing"
+
+func TestAlpha(t *testing.T) {}
+
+func TestBravo(t *testing.T) {
+	// func TestInner(t *testing.T) {}
+}
+
+func TestCharlie(t *testing.T) {}
+
+// func TestOld(t *testing.T) {
+// 	t.Skip("flaky")
+// }
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := ReorderSource("commented_test.go", []byte(source), WithCommented(test.policy))
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expect, string(actual))
+		})
+	}
+}
+
+func TestReorderSource_directives(t *testing.T) {
+	t.Parallel()
+
+	source := `//go:build unit
+
+package main
+
+import "testing"
+
+//go:generate go run gen.go
+
+// Copyright 2025 The Authors.
+//nolint:paralleltest // shares a global
+func TestEcho(t *testing.T) {}
+
+//go:noinline
+func TestDelta(t *testing.T) {}
+
+//lint:ignore U1000 kept for later
+func TestCharlie(t *testing.T) {}
+
+//go:generate stringer -type=Kind
+func TestBravo(t *testing.T) {}
+
+// TestAlpha checks the first case.
+//
+//nolint:funlen
+func TestAlpha(t *testing.T) {}
+`
+	expect := `//go:build unit
+
+package main
+
+import "testing"
+
+//go:generate go run gen.go
+
+// Copyright 2025 The Authors.
+
+// TestAlpha checks the first case.
+//
+//nolint:funlen
+func TestAlpha(t *testing.T) {}
+
+//go:generate stringer -type=Kind
+func TestBravo(t *testing.T) {}
+
+//lint:ignore U1000 kept for later
+func TestCharlie(t *testing.T) {}
+
+//go:noinline
+func TestDelta(t *testing.T) {}
+
+//nolint:paralleltest // shares a global
+func TestEcho(t *testing.T) {}
+`
+	actual, err := ReorderSource("directives_test.go", []byte(source))
+
+	require.NoError(t, err)
+	assert.Equal(t, expect, string(actual))
+}
+
+func TestReorderSource_floating(t *testing.T) {
+	t.Parallel()
+
+	source := `package main
+
+import "testing"
+
+func TestCharlie(t *testing.T) {}
+
+// TODO: cover the error cases.
+
+// TestBravo checks the second case.
+func TestBravo(t *testing.T) {}
+
+// func TestOld(t *testing.T) {}
+
+func TestAlpha(t *testing.T) {}
+`
+	tests := []struct {
+		name   string
+		policy FloatingPolicy
+		expect string
+	}{
+		{
+			name:   "attach",
+			policy: FloatingAttach,
+			expect: `package main
+
+import "testing"
+
+// func TestOld(t *testing.T) {}
+
+func TestAlpha(t *testing.T) {}
+
+// TODO: cover the error cases.
+
+// TestBravo checks the second case.
+func TestBravo(t *testing.T) {}
+
+func TestCharlie(t *testing.T) {}
+`,
+		},
+		{
+			name:   "keep",
+			policy: FloatingKeep,
+			expect: `package main
+
+import "testing"
+
+func TestAlpha(t *testing.T) {}
+
+// TODO: cover the error cases.
+
+// TestBravo checks the second case.
+func TestBravo(t *testing.T) {}
+
+// func TestOld(t *testing.T) {}
+
+func TestCharlie(t *testing.T) {}
+`,
+		},
+		{
+			name:   "previous",
+			policy: FloatingPrevious,
+			expect: `package main
+
+import "testing"
+
+func TestAlpha(t *testing.T) {}
+
+// TestBravo checks the second case.
+func TestBravo(t *testing.T) {}
+
+// func TestOld(t *testing.T) {}
+
+func TestCharlie(t *testing.T) {}
+
+// TODO: cover the error cases.
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := ReorderSource("floating_test.go", []byte(source),
+				WithPlacement(PlaceInPlace), WithFloating(test.policy))
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expect, string(actual))
+		})
+	}
+}
+
+func TestReorderSource_gofmt(t *testing.T) {
+	t.Parallel()
+
+	source := "package main\r\n\r\nimport \"testing\"\r\n\r\nfunc TestB(t *testing.T)  {}\r\n" +
+		"\r\nfunc TestA(t *testing.T) {\r\n  t.Log(\"a\")\r\n}"
+	expect := "package main\r\n\r\nimport \"testing\"\r\n\r\nfunc TestA(t *testing.T) {\r\n\tt.Log(\"a\")\r\n}\r\n" +
+		"\r\nfunc TestB(t *testing.T) {}"
+
+	actual, err := ReorderSource("gofmt_test.go", []byte(source), WithGofmt(), WithBlankLines(3))
+
+	require.NoError(t, err)
+	assert.Equal(t, expect, string(actual), "formatted and then restored to the source style")
+
+	// The banner template leaves a comment unterminated
+	_, err = ReorderSource("gofmt_test.go", []byte(source), WithGofmt(), WithBanners("/* %s"))
+
+	var formatErr *FormatError
+
+	require.ErrorAs(t, err, &formatErr)
+	assert.Equal(t, "gofmt_test.go", formatErr.Filename)
+}
+
+func TestReorderSource_golden(t *testing.T) {
+	t.Parallel()
+
+	input, err := os.ReadFile("testdata/test_sample1_before")
+	require.NoError(t, err)
+
+	expect, err := os.ReadFile("testdata/test_sample1_expect")
+	require.NoError(t, err)
+
+	actual, err := ReorderSource("test_sample1_before", input)
+
+	require.NoError(t, err)
+	assert.Equal(t, string(expect), string(actual))
+}
+
+func TestReorderSource_group_suites(t *testing.T) {
+	t.Parallel()
+
+	input, err := os.ReadFile("testdata/test_sample3_before")
+	require.NoError(t, err)
+
+	expect, err := os.ReadFile("testdata/test_sample3_expect_group_suites")
+	require.NoError(t, err)
+
+	for _, opts := range [][]Option{
+		{WithGroupSuites()},
+		{WithGroupSuites(), WithStrict()},
+	} {
+		actual, err := ReorderSource("test_sample3_before", input, opts...)
+
+		require.NoError(t, err)
+		assert.Equal(t, string(expect), string(actual))
+	}
+}
+
+func TestReorderSource_header_is_anchored(t *testing.T) {
+	t.Parallel()
+
+	source := `//go:build unit
+
+// Package main is an example.
+package main
+
+import "testing"
+
+// Copyright 2025 The Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// TestZulu checks the last case.
+func TestZulu(t *testing.T) {}
+
+func TestAlpha(t *testing.T) {}
+`
+	expect := `//go:build unit
+
+// Package main is an example.
+package main
+
+import "testing"
+
+// Copyright 2025 The Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+func TestAlpha(t *testing.T) {}
+
+// TestZulu checks the last case.
+func TestZulu(t *testing.T) {}
+`
+	actual, err := ReorderSource("header_test.go", []byte(source))
+
+	require.NoError(t, err)
+	assert.Equal(t, expect, string(actual))
+
+	source = "package main\n\nimport \"testing\"\n\n" +
+		"// TestZ checks the Copyright notice.\nfunc TestZ(t *testing.T) {}\n\n" +
+		"// TestA checks A.\nfunc TestA(t *testing.T) {}\n"
+	expect = "package main\n\nimport \"testing\"\n\n// TestA checks A.\nfunc TestA(t *testing.T) {}\n\n" +
+		"// TestZ checks the Copyright notice.\nfunc TestZ(t *testing.T) {}\n"
+
+	for _, opts := range [][]Option{nil, {WithFloating(FloatingKeep)}} {
+		actual, err = ReorderSource("header_test.go", []byte(source), append(opts, WithCheckEquivalence())...)
+
+		require.NoError(t, err)
+		assert.Equal(t, expect, string(actual), "a doc comment mentioning the copyright is not a header")
+	}
+}
+
+func TestReorderSource_init_is_never_moved(t *testing.T) {
+	t.Parallel()
+
+	source := `package main
+
+import "testing"
+
+func TestBeta(t *testing.T) {}
+
+func init() {}
+
+func TestAlpha(t *testing.T) {}
+`
+	matchAll := WithMatch(func(*ast.FuncDecl) bool { return true })
+
+	actual, err := ReorderSource("init_test.go", []byte(source), matchAll, WithPlacement(PlaceInPlace))
+	require.NoError(t, err)
+
+	expect := `package main
+
+import "testing"
+
+func TestAlpha(t *testing.T) {}
+
+func init() {}
+
+func TestBeta(t *testing.T) {}
+`
+	assert.Equal(t, expect, string(actual))
+
+	moved, err := ReorderSource("init_test.go", []byte(source), matchAll, WithPlacement(PlaceInPlace), WithMoveInit())
+	require.NoError(t, err)
+	assert.Less(t, strings.Index(string(moved), "func TestBeta"), strings.Index(string(moved), "func init"),
+		"init is sorted with the other functions when moving is allowed")
+}
+
+func TestReorderSource_init_repeated(t *testing.T) {
+	t.Parallel()
+
+	source := `package main
+
+func zulu() {}
+
+func init() { println(2) }
+
+func _() { println(2) }
+
+func alpha() {}
+
+func init() { println(1) }
+
+func _() { println(1) }
+`
+	expect := `package main
+
+func _() { println(2) }
+
+func _() { println(1) }
+
+func alpha() {}
+
+func init() { println(2) }
+
+func init() { println(1) }
+
+func zulu() {}
+`
+	actual, err := ReorderSource("init.go", []byte(source), WithMode(ModeFuncs), WithMoveInit())
+
+	require.NoError(t, err)
+	assert.Equal(t, expect, string(actual), "equally named functions are moved as units in their original order")
+}
+
+func TestReorderSource_invalid_syntax(t *testing.T) {
+	t.Parallel()
+
+	actual, err := ReorderSource("invalid.go", []byte(`package invalid syntax`))
+
+	require.Error(t, err)
+	assert.Nil(t, actual, "no output should be returned on error")
+}
+
+func TestReorderSource_mode_funcs(t *testing.T) {
+	t.Parallel()
+
+	input, err := os.ReadFile("testdata/test_sample4_before")
+	require.NoError(t, err)
+
+	expect, err := os.ReadFile("testdata/test_sample4_expect_funcs")
+	require.NoError(t, err)
+
+	actual, err := ReorderSource("shapes.go", input, WithMode(ModeFuncs), WithPlacement(PlaceInPlace))
+
+	require.NoError(t, err)
+	assert.Equal(t, string(expect), string(actual))
+	assert.Equal(t,
+		[]string{"Describe", "NewCircle", "Area", "init", "describe", "scale"},
+		funcNames(t, actual))
+}
+
+func TestReorderSource_mode_types(t *testing.T) {
+	t.Parallel()
+
+	input, err := os.ReadFile("testdata/test_sample6_before")
+	require.NoError(t, err)
+
+	expect, err := os.ReadFile("testdata/test_sample6_expect_types")
+	require.NoError(t, err)
+
+	actual, err := ReorderSource("store.go", input, WithMode(ModeTypes), WithPlacement(PlaceInPlace))
+
+	require.NoError(t, err)
+	assert.Equal(t, string(expect), string(actual))
+	assert.Equal(t,
+		[]string{"NewItem", "NewStore", "Get", "Set", "lock", "Open", "validate"},
+		funcNames(t, actual))
+}
+
+func TestReorderSource_test_main(t *testing.T) {
+	t.Parallel()
+
+	source := `package main
+
+import "testing"
+
+func TestParse(t *testing.T) {}
+
+func TestMain(m *testing.M) {}
+
+func TestLoad(t *testing.T) {}
+`
+
+	tests := []struct {
+		name   string
+		policy TestMainPolicy
+		expect []string
+	}{
+		{name: "sorted", policy: TestMainSorted, expect: []string{"TestLoad", "TestMain", "TestParse"}},
+		{name: "first", policy: TestMainFirst, expect: []string{"TestMain", "TestLoad", "TestParse"}},
+		{name: "keep", policy: TestMainKeep, expect: []string{"TestMain", "TestLoad", "TestParse"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			output, err := ReorderSource("main_test.go", []byte(source), WithTestMain(test.policy))
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expect, funcNames(t, output))
+		})
+	}
+
+	// With in-place placement, a kept TestMain does not move at all
+	output, err := ReorderSource("main_test.go", []byte(source), WithTestMain(TestMainKeep), WithPlacement(PlaceInPlace))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"TestLoad", "TestMain", "TestParse"}, funcNames(t, output))
+}
+
+func TestReorderSource_kinds(t *testing.T) {
+	t.Parallel()
+
+	source := `package main
+
+import "testing"
+
+func ExampleFoo() {}
+
+func BenchmarkFoo(b *testing.B) {}
+
+func TestBar(t *testing.T) {}
+
+func FuzzFoo(f *testing.F) {}
+
+func BenchmarkBar(b *testing.B) {}
+
+func TestFoo(t *testing.T) {}
+`
+
+	tests := []struct {
+		name   string
+		kinds  []Kind
+		expect []string
+	}{
+		{
+			name:   "default reorders only tests",
+			kinds:  nil,
+			expect: []string{"ExampleFoo", "BenchmarkFoo", "FuzzFoo", "BenchmarkBar", "TestBar", "TestFoo"},
+		},
+		{
+			name:   "all kinds in conventional order",
+			kinds:  AllKinds(),
+			expect: []string{"TestBar", "TestFoo", "BenchmarkBar", "BenchmarkFoo", "FuzzFoo", "ExampleFoo"},
+		},
+		{
+			name:   "custom kind order",
+			kinds:  []Kind{KindExample, KindBenchmark},
+			expect: []string{"TestBar", "FuzzFoo", "TestFoo", "ExampleFoo", "BenchmarkBar", "BenchmarkFoo"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			output, err := ReorderSource("kinds_test.go", []byte(source), WithKinds(test.kinds...))
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expect, funcNames(t, output))
+		})
+	}
+}
+
+func TestReorderSource_sort_decls(t *testing.T) {
+	t.Parallel()
+
+	input, err := os.ReadFile("testdata/test_sample7_before")
+	require.NoError(t, err)
+
+	expect, err := os.ReadFile("testdata/test_sample7_expect_sort_decls")
+	require.NoError(t, err)
+
+	actual, err := ReorderSource("config.go", input, WithMode(ModeFuncs), WithSortDecls())
+
+	require.NoError(t, err)
+	assert.Equal(t, string(expect), string(actual))
+
+	again, err := ReorderSource("config.go", actual, WithMode(ModeFuncs), WithSortDecls())
+
+	require.NoError(t, err)
+	assert.Equal(t, string(actual), string(again), "sorting the declarations should be idempotent")
+
+	source := "package x\n\nfunc helper() {}\n\nconst limit = 1\n\nfunc TestB(t *testing.T) {}\n\n" +
+		"var global = 2\n\nfunc TestA(t *testing.T) {}\n"
+	expect = []byte("package x\n\nfunc helper() {}\n\nconst limit = 1\n\nvar global = 2\n\n" +
+		"func TestA(t *testing.T) {}\n\nfunc TestB(t *testing.T) {}\n")
+
+	actual, err = ReorderSource("config_test.go", []byte(source), WithSortDecls())
+
+	require.NoError(t, err)
+	assert.Equal(t, string(expect), string(actual), "the declarations never move with ModeTests")
+}
+
+func TestReorderSource_source_style(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		source string
+		expect string
+	}{
+		{
+			name:   "crlf line endings",
+			source: "package main\r\n\r\nfunc TestB(t *testing.T) {\r\n}\r\n\r\nfunc TestA(t *testing.T) {}\r\n",
+			expect: "package main\r\n\r\nfunc TestA(t *testing.T) {}\r\n\r\nfunc TestB(t *testing.T) {\r\n}\r\n",
+		},
+		{
+			name:   "mixed line endings",
+			source: "package main\r\n\r\nfunc TestB(t *testing.T) {\n}\r\n\r\nfunc TestA(t *testing.T) {}\r\n",
+			expect: "package main\r\n\r\nfunc TestA(t *testing.T) {}\r\n\r\nfunc TestB(t *testing.T) {\n}\r\n",
+		},
+		{
+			name: "line feed moves with its line",
+			source: "package main\r\n\r\nfunc TestB(t *testing.T) {\r\n}\n\r\nfunc helper() {\r\n}\r\n\r\n" +
+				"func TestA(t *testing.T) {\r\n}\r\n",
+			expect: "package main\r\n\r\nfunc helper() {\r\n}\r\n\r\nfunc TestA(t *testing.T) {\r\n}\r\n\r\n" +
+				"func TestB(t *testing.T) {\r\n}\n",
+		},
+		{
+			name:   "byte order mark",
+			source: "\ufeffpackage main\n\nfunc TestB(t *testing.T) {}\n\nfunc TestA(t *testing.T) {}\n",
+			expect: "\ufeffpackage main\n\nfunc TestA(t *testing.T) {}\n\nfunc TestB(t *testing.T) {}\n",
+		},
+		{
+			name:   "no final newline",
+			source: "package main\n\nfunc TestB(t *testing.T) {}\n\nfunc TestA(t *testing.T) {}",
+			expect: "package main\n\nfunc TestA(t *testing.T) {}\n\nfunc TestB(t *testing.T) {}",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := ReorderSource("style_test.go", []byte(test.source))
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expect, string(actual))
+		})
+	}
+}
+
+func TestReorderSource_spacing(t *testing.T) {
+	t.Parallel()
+
+	source := `package main
+import "testing"
+
+
+var x = 1
+func TestB(t *testing.T) {
+
+
+	t.Log(x)
+}
+
+
+
+// TODO: more cases
+func TestA(t *testing.T) {}
+`
+	tests := []struct {
+		name   string
+		opts   []Option
+		expect string
+	}{
+		{
+			name: "preserve",
+			opts: []Option{WithSpacing(SpacingPreserve)},
+			expect: `package main
+import "testing"
+
+
+var x = 1
+
+// TODO: more cases
+func TestA(t *testing.T) {}
+
+func TestB(t *testing.T) {
+
+
+	t.Log(x)
+}
+`,
 		},
 		{
-			name:         "line does not match any function start",
-			lineNumber:   3,
-			expectedFunc: "",
+			name: "exactly one",
+			opts: []Option{WithSpacing(SpacingNormalize)},
+			expect: `package main
+
+import "testing"
+
+var x = 1
+
+// TODO: more cases
+func TestA(t *testing.T) {}
+
+func TestB(t *testing.T) {
+
+
+	t.Log(x)
+}
+`,
 		},
 		{
-			name:         "line is inside function but not start",
-			lineNumber:   6,
-			expectedFunc: "",
+			name: "exactly two",
+			opts: []Option{WithSpacing(SpacingNormalize), WithBlankLines(2)},
+			expect: `package main
+
+
+import "testing"
+
+
+var x = 1
+
+
+// TODO: more cases
+func TestA(t *testing.T) {}
+
+
+func TestB(t *testing.T) {
+
+
+	t.Log(x)
+}
+`,
 		},
 	}
 
@@ -604,83 +1749,135 @@ func Test_findTestFunctionAtLine_golden(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
 
-			result := findTestFunctionAtLine(test.lineNumber, testFuncPos)
-			assert.Equal(t, test.expectedFunc, result)
+			actual, err := ReorderSource("spacing_test.go", []byte(source), test.opts...)
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expect, string(actual))
 		})
 	}
 }
 
-func Test_isCommentBeforeTestFunction_golden(t *testing.T) {
+func TestReorderSource_strict(t *testing.T) {
 	t.Parallel()
 
-	lines := []string{
-		"package main",         // 0
-		"",                     // 1
-		"import (",             // 2
-		"    \"testing\"",      // 3
-		"    \"fmt\"",          // 4
-		")",                    // 5
-		"",                     // 6
-		"// Comment for test",  // 7
-		"",                     // 8
-		"func Test_ex() {",     // 9
-		"}",                    // 10
-		"",                     // 11
-		"regular line",         // 12
-		"",                     // 13
-		"// Direct comment",    // 14
-		"func Test_direct() {", // 15
-		"}",                    // 16
-		"",                     // 17
-		"func otherFunc() {",   // 18
-		"}",                    // 19
-		"// After other func",  // 20
-		"",                     // 21
-		"func Test_after() {",  // 22
-		"}",                    // 23
-	}
+	source := `package main
+
+import tt "testing"
+
+func TestZulu(t *tt.T) {}
+
+func Testify() {}
+
+func TestdataPath() string { return "testdata" }
+
+func TestAlpha(t *tt.T) {}
+`
+
+	lenient, err := ReorderSource("strict_test.go", []byte(source))
+	require.NoError(t, err)
+	assert.Less(t, strings.Index(string(lenient), "func TestdataPath"), strings.Index(string(lenient), "func Testify"),
+		"without strict mode, helpers are sorted as tests")
+
+	strict, err := ReorderSource("strict_test.go", []byte(source), WithStrict())
+	require.NoError(t, err)
+
+	expect := `package main
+
+import tt "testing"
+
+func Testify() {}
+
+func TestdataPath() string { return "testdata" }
+
+func TestAlpha(t *tt.T) {}
+
+func TestZulu(t *tt.T) {}
+`
+	assert.Equal(t, expect, string(strict))
+}
+
+func TestReorderSource_trailing_comments(t *testing.T) {
+	t.Parallel()
+
+	source := `package main
+
+import "testing"
+
+func TestCharlie(t *testing.T) {
+} // end of TestCharlie
+
+func TestBravo(t *testing.T) {
+} /* end of
+TestBravo */
+
+func helper() {}
+
+func TestAlpha(t *testing.T) {}
+
+// end of tests
+`
+	expect := `package main
+
+import "testing"
+
+func helper() {}
+
+func TestAlpha(t *testing.T) {}
+
+func TestBravo(t *testing.T) {
+} /* end of
+TestBravo */
+
+func TestCharlie(t *testing.T) {
+} // end of TestCharlie
+
+// end of tests
+`
+	actual, err := ReorderSource("trailing_test.go", []byte(source))
+
+	require.NoError(t, err)
+	assert.Equal(t, expect, string(actual))
+
+	withTrailer, err := ReorderSource("trailing_test.go", []byte(source), WithBlankLines(2))
+	require.NoError(t, err)
+
+	withoutTrailer, err := ReorderSource("trailing_test.go", []byte(strings.TrimSuffix(source, "// end of tests\n")),
+		WithBlankLines(2))
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(string(withTrailer), string(withoutTrailer)),
+		"the trailer does not change the separators of the sorted functions")
+	assert.Contains(t, string(withTrailer), "} // end of TestCharlie\n\n\n// end of tests\n")
+}
+
+func TestReorder_errors(t *testing.T) {
+	t.Parallel()
+
+	errDummy := errors.New("dummy error")
 
 	tests := []struct {
-		name              string
-		lineIndex         int
-		testFuncStartLine int
-		expectedIsComment bool
+		name        string
+		src         io.Reader
+		dst         io.Writer
+		expectError string
 	}{
 		{
-			name:              "comment separated by empty line, no preceding code (with multiline import)",
-			lineIndex:         7,    // "// Comment for test"
-			testFuncStartLine: 10,   // "func Test_ex() {" (1-based: 9+1=10)
-			expectedIsComment: true, // No other code before this comment (package and import block don't count)
-		},
-		{
-			name:              "empty line before test function",
-			lineIndex:         8,     // ""
-			testFuncStartLine: 10,    // "func Test_ex() {" (1-based: 9+1=10)
-			expectedIsComment: false, // Empty lines are never part of test functions
-		},
-		{
-			name:              "direct comment before test function",
-			lineIndex:         14,   // "// Direct comment"
-			testFuncStartLine: 16,   // "func Test_direct() {" (1-based: 15+1=16)
-			expectedIsComment: true, // Directly precedes test function
-		},
-		{
-			name:              "comment after other function",
-			lineIndex:         20,    // "// After other func"
-			testFuncStartLine: 23,    // "func Test_after() {" (1-based: 22+1=23)
-			expectedIsComment: false, // Has other code (otherFunc) before it
+			name:        "failing_reader",
+			src:         iotest.ErrReader(errDummy),
+			dst:         io.Discard,
+			expectError: "failed to read input: dummy error",
 		},
 		{
-			name:              "line after test function",
-			lineIndex:         11, // ""
-			testFuncStartLine: 10, // "func Test_ex() {" (1-based: 9+1=10)
-			expectedIsComment: false,
+			name:        "invalid_syntax",
+			src:         strings.NewReader(`package invalid syntax`),
+			dst:         io.Discard,
+			expectError: "failed to parse Go file",
 		},
 		{
-			name:              "regular line not related to test",
-			lineIndex:         12, // "regular line"
-			testFuncStartLine: 10, // "func Test_ex() {" (1-based: 9+1=10)
-			expectedIsComment: false,
+			name:        "failing_writer",
+			src:         strings.NewReader("package main\n"),
+			dst:         failingWriter{err: errDummy},
+			expectError: "failed to write output: dummy error",
 		},
 	}
 
@@ -688,49 +1885,172 @@ func Test_isCommentBeforeTestFunction_golden(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
 
-			result := isCommentBeforeTestFunction(test.lineIndex, lines, test.testFuncStartLine)
-			assert.Equal(t, test.expectedIsComment, result)
+			err := Reorder("input.go", test.src, test.dst)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.expectError)
 		})
 	}
 }
 
-func Test_isCommentOrEmpty_golden(t *testing.T) {
+func TestReorder_golden(t *testing.T) {
+	t.Parallel()
+
+	input, err := os.Open("testdata/test_sample1_before")
+	require.NoError(t, err)
+
+	defer func() { _ = input.Close() }()
+
+	expect, err := os.ReadFile("testdata/test_sample1_expect")
+	require.NoError(t, err)
+
+	var output bytes.Buffer
+
+	err = Reorder("test_sample1_before", input, &output)
+
+	require.NoError(t, err)
+	assert.Equal(t, string(expect), output.String())
+}
+
+// ============================================================================
+//  Private Functions (ABC Order)
+// ============================================================================
+
+func Test_buildTestFunctionPositions_golden(t *testing.T) {
+	t.Parallel()
+
+	source := `package main
+
+import "testing"
+
+func Test_alpha(t *testing.T) {
+	// line 6
+}
+
+func regularFunc() {
+	// line 10
+}
+
+func Test_beta(t *testing.T) {
+	// line 14
+	// line 15
+}`
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", source, parser.ParseComments)
+	require.NoError(t, err)
+
+	positions := buildTestFunctionPositions(file, fset, Options{})
+
+	expected := map[string][2]int{
+		"Test_alpha": {5, 7},   // Lines 5-7
+		"Test_beta":  {13, 16}, // Lines 13-16
+	}
+
+	assert.Equal(t, expected, positions)
+	assert.NotContains(t, positions, "regularFunc", "regular functions should not be included")
+}
+
+func Test_extractTestFunctionWithComments_golden(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{
+		"package main",                      // 0
+		"",                                  // 1
+		"import \"testing\"",                // 2
+		"",                                  // 3
+		"// Comment for test",               // 4
+		"// Another comment",                // 5
+		"func Test_example(t *testing.T) {", // 6
+		"	// Test body",                     // 7
+		"	pass := true",                     // 8
+		"}",                                 // 9
+		"",                                  // 10
+		"func regularFunc() {",              // 11
+		"}",                                 // 12
+	}
+
+	funcInfo := funcPos{name: "Test_example", commentStart: 3, startLine: 6, endLine: 9}
+
+	testFunc := extractTestFunctionWithComments(lines, funcInfo, Options{})
+
+	assert.Equal(t, "Test_example", testFunc.Name)
+
+	expectedLines := []string{
+		"", // Empty line before comments
+		"// Comment for test",
+		"// Another comment",
+		"func Test_example(t *testing.T) {",
+		"	// Test body",
+		"	pass := true",
+		"}",
+	}
+	assert.Equal(t, expectedLines, testFunc.Lines)
+}
+
+func Test_buildCommentEnds_golden(t *testing.T) {
+	t.Parallel()
+
+	source := "package main\n\nimport \"testing\"\n\n// Header\n\nfunc TestB(t *testing.T) {\n}\n\n" +
+		"// TODO: more cases\n\n// TestA doc.\nfunc TestA(t *testing.T) {\n}\n"
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "comments.go", source, parser.ParseComments)
+	require.NoError(t, err)
+
+	assert.Empty(t, buildCommentEnds(file, fset, Options{}))
+	assert.Equal(t, map[int]int{7: 9}, buildCommentEnds(file, fset, Options{Floating: FloatingPrevious}),
+		"the TODO note moves with TestB, the header never moves")
+}
+
+func Test_buildCommentStarts_golden(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name     string
-		line     string
-		expected bool
+		name   string
+		source string
+		expect map[int]int // 0-based declaration start line -> first line of its comments
 	}{
 		{
-			name:     "empty line",
-			line:     "",
-			expected: true,
+			name:   "preceding comments",
+			source: "package main\n\n// Comment 1\n// Comment 2\nfunc Test_example(t *testing.T) {\n}",
+			expect: map[int]int{4: 1},
 		},
 		{
-			name:     "single line comment",
-			line:     "// This is a comment",
-			expected: true,
+			name:   "no preceding comments",
+			source: "package main\n\nfunc regularFunc() {\n}\nfunc Test_example(t *testing.T) {\n}",
+			expect: map[int]int{2: 1, 4: 4},
 		},
 		{
-			name:     "multi-line comment start",
-			line:     "/* This is a multi-line comment",
-			expected: true,
+			name:   "detached comment of the file header",
+			source: "package main\n\n// Comment\n\n// Another comment\nfunc Test_example(t *testing.T) {\n}",
+			expect: map[int]int{5: 3},
 		},
 		{
-			name:     "regular code line",
-			line:     "func TestExample(t *testing.T) {",
-			expected: false,
+			name:   "license attached to the first declaration",
+			source: "package main\n\n// Copyright 2025 Foo\nfunc Test_example(t *testing.T) {\n}",
+			expect: map[int]int{3: 3},
 		},
 		{
-			name:     "whitespace only",
-			line:     "   \t  ",
-			expected: false,
+			name:   "detached comment after the first declaration",
+			source: "package main\n\nvar x = 1\n\n// Comment\n\nfunc Test_example(t *testing.T) {\n}",
+			expect: map[int]int{2: 1, 6: 3},
 		},
 		{
-			name:     "line with comment prefix inside",
-			line:     "string := \"// not a comment\"",
-			expected: false,
+			name:   "block comment with plain middle lines",
+			source: "package main\n\nvar x = 1\n\n/*\nDoc of the test.\n*/\nfunc Test_example(t *testing.T) {\n}",
+			expect: map[int]int{2: 1, 7: 3},
+		},
+		{
+			name:   "trailing comment of the previous declaration",
+			source: "package main\n\nvar x = 1 /* trailing\ncomment */\n\nfunc Test_example(t *testing.T) {\n}",
+			expect: map[int]int{2: 1, 5: 4},
+		},
+		{
+			name:   "comment marker inside a string literal",
+			source: "package main\n\nvar url = \"http://example.com\"\n\nfunc Test_example(t *testing.T) {\n}",
+			expect: map[int]int{2: 1, 4: 3},
 		},
 	}
 
@@ -738,79 +2058,175 @@ func Test_isCommentOrEmpty_golden(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
 
-			result := isCommentOrEmpty(test.line)
-			assert.Equal(t, test.expected, result)
+			fset := token.NewFileSet()
+
+			file, err := parser.ParseFile(fset, "comments.go", test.source, parser.ParseComments)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expect, buildCommentStarts(file, fset, Options{}))
 		})
 	}
 }
 
-//nolint:funlen // allow long test function (64 > 60)
-func Test_isLinePartOfTestFunction_golden(t *testing.T) {
+func Test_buildCommentStarts_floating(t *testing.T) {
+	t.Parallel()
+
+	source := "package main\n\nvar x = 1\n\n// TODO: more cases\n\n// Test_example doc.\nfunc Test_example(t *testing.T) {\n}"
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "comments.go", source, parser.ParseComments)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[int]int{2: 1, 7: 3}, buildCommentStarts(file, fset, Options{}))
+	assert.Equal(t, map[int]int{2: 1, 7: 5}, buildCommentStarts(file, fset, Options{Floating: FloatingKeep}),
+		"only the doc comment and the blank lines before it are owned")
+}
+
+func Test_findMarker(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{
+		"package main",       // 0
+		"",                   // 1
+		"func helper() {",    // 2
+		"\t// Tests",         // 3
+		"\ts := `",           // 4
+		"// Tests",           // 5
+		"`",                  // 6
+		"\t_ = s",            // 7
+		"}",                  // 8
+		"",                   // 9
+		"var x = 1 // Tests", // 10
+		"// Tests",           // 11
+	}
+	options := newOptions(WithMarker("// Tests"))
+
+	assert.Equal(t, 11, findMarker(lines, options))
+	assert.Equal(t, -1, findMarker(lines[:11], options), "only top-level comments at column 1 are markers")
+	assert.Equal(t, -1, findMarker(lines[2:], options), "the lines do not parse")
+}
+
+func Test_findTrailerStart(t *testing.T) {
 	t.Parallel()
 
 	lines := []string{
-		"package main",         // 0
-		"",                     // 1
-		"// Comment for test",  // 2
-		"func Test_alpha() {",  // 3
-		"	// test body",        // 4
-		"}",                    // 5
-		"",                     // 6
-		"func regularFunc() {", // 7
-		"}",                    // 8
+		"package main",    // 0
+		"",                // 1
+		"func helper() {", // 2
+		"}",               // 3
+		"",                // 4
+		"// end of tests", // 5
+		"",                // 6
 	}
 
-	testFuncPos := map[string][2]int{
-		"Test_alpha": {4, 6}, // func Test_alpha() on line 3 (0-based) = line 4 (1-based)
+	assert.Equal(t, 5, findTrailerStart(nil, lines))
+	assert.Equal(t, 5, findTrailerStart([]TestFunction{{Offset: 5}}, lines),
+		"the test function was found before the comment")
+	assert.Equal(t, 7, findTrailerStart([]TestFunction{{Offset: 6}}, lines),
+		"the test function was found after the comment")
+	assert.Equal(t, 5, findTrailerStart(nil, lines[2:]), "the lines do not parse")
+	assert.Equal(t, 4, findTrailerStart(nil, lines[:4]), "no trailing comment")
+}
+
+func Test_isHeaderComment(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		comment string
+		expect  bool
+	}{
+		{name: "copyright line", comment: "// Copyright 2025 The Authors.\n// SPDX-License-Identifier: MIT", expect: true},
+		{name: "license identifier", comment: "// SPDX-License-Identifier: MIT", expect: true},
+		{name: "license block", comment: "/*\n * Copyright 2025 The Authors.\n */", expect: true},
+		{name: "file directive", comment: "// TestZ checks Z.\n//go:build unit", expect: true},
+		{name: "doc mentioning the copyright", comment: "// TestZ checks the Copyright notice.", expect: false},
+		{name: "copyright on a later line", comment: "// TestZ checks the header.\n// Copyright is printed.", expect: false},
+		{name: "declaration directive", comment: "//nolint:funlen", expect: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := "package x\n" + test.comment + "\nfunc f() {}\n"
+
+			file, err := parser.ParseFile(token.NewFileSet(), "x.go", source, parser.ParseComments)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expect, isHeaderComment(file.Comments[0]))
+		})
 	}
+}
+
+func Test_isStandaloneComment(t *testing.T) {
+	t.Parallel()
+
+	source := `// Package x is documented.
+package x
+
+// Floating comment.
+
+// Doc comment.
+var registry = 1
+
+func f() {
+// Inner comment.
+}
+
+  // Indented comment.
+`
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "x.go", source, parser.ParseComments)
+	require.NoError(t, err)
+	require.Len(t, file.Comments, 5)
+
+	assert.False(t, isStandaloneComment(file, fset, file.Comments[0]), "package doc")
+	assert.True(t, isStandaloneComment(file, fset, file.Comments[1]), "floating comment")
+	assert.False(t, isStandaloneComment(file, fset, file.Comments[2]), "declaration doc")
+	assert.False(t, isStandaloneComment(file, fset, file.Comments[3]), "comment within a declaration")
+	assert.False(t, isStandaloneComment(file, fset, file.Comments[4]), "comment not at column 1")
+}
+
+func Test_findImportsEnd_golden(t *testing.T) {
+	t.Parallel()
 
 	tests := []struct {
-		name        string
-		lineIndex   int
-		expectedIs  bool
-		description string
+		name   string
+		lines  []string
+		expect int
 	}{
 		{
-			name:        "empty line before comment",
-			lineIndex:   1,
-			expectedIs:  true, // Empty line before comment is considered part
-			description: "empty line that precedes comment before test",
+			name:   "import block",
+			lines:  []string{"package main", "", "import (", "\t\"fmt\"", ")", "", "func main() {}"},
+			expect: 4,
 		},
 		{
-			name:        "comment before test function",
-			lineIndex:   2,
-			expectedIs:  false, // Comment is NOT part of test function itself, but precedes it
-			description: "comment preceding test function",
+			name:   "single imports",
+			lines:  []string{"package main", "import \"fmt\"", "import \"os\"", "var x = 1"},
+			expect: 2,
 		},
 		{
-			name:        "test function start",
-			lineIndex:   3,
-			expectedIs:  true,
-			description: "start of test function",
+			name:   "one-line import block",
+			lines:  []string{"package main", "import (\"fmt\")", "func main() {}"},
+			expect: 1,
 		},
 		{
-			name:        "inside test function",
-			lineIndex:   4,
-			expectedIs:  true,
-			description: "inside test function body",
+			name:   "no imports",
+			lines:  []string{"// Package doc", "package main", "", "const x = 1"},
+			expect: 1,
 		},
 		{
-			name:        "test function end",
-			lineIndex:   5,
-			expectedIs:  true,
-			description: "end of test function",
+			name:   "package doc with a declaration line",
+			lines:  []string{"/*", "func main() {}", "*/", "package main", "", "func main() {}"},
+			expect: 3,
 		},
 		{
-			name:        "line after test function",
-			lineIndex:   6,
-			expectedIs:  false,
-			description: "empty line after test function",
-		},
-		{
-			name:        "regular function",
-			lineIndex:   7,
-			expectedIs:  false,
-			description: "regular function not a test",
+			name:   "no package clause",
+			lines:  []string{"func main() {}"},
+			expect: -1,
 		},
 	}
 
@@ -818,12 +2234,34 @@ func Test_isLinePartOfTestFunction_golden(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
 
-			result := isLinePartOfTestFunction(test.lineIndex, lines, testFuncPos)
-			assert.Equal(t, test.expectedIs, result, test.description)
+			assert.Equal(t, test.expect, findImportsEnd(test.lines))
 		})
 	}
 }
 
+func Test_findOwnedCommentStart_golden(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{
+		"package main",                      // 0
+		"",                                  // 1
+		"// Tests",                          // 2
+		"",                                  // 3
+		"// Comment for test",               // 4
+		"func Test_example(t *testing.T) {", // 5
+		"}",                                 // 6
+	}
+
+	funcInfo := funcPos{name: "Test_example", commentStart: 1, startLine: 5, endLine: 6}
+
+	assert.Equal(t, 1, findOwnedCommentStart(lines, funcInfo, Options{}),
+		"without marker placement, all preceding comments are owned")
+	assert.Equal(t, 1, findOwnedCommentStart(lines, funcInfo, Options{Marker: "// Tests"}),
+		"marker is ignored unless placement is after-marker")
+	assert.Equal(t, 3, findOwnedCommentStart(lines, funcInfo, Options{Marker: "// Tests", Placement: PlaceAfterMarker}),
+		"comments owned by the function start after the marker")
+}
+
 func Test_separateTestAndNonTestContent_golden(t *testing.T) {
 	t.Parallel()
 
@@ -851,7 +2289,12 @@ func Test_separateTestAndNonTestContent_golden(t *testing.T) {
 		"Test_beta":  {14, 16}, // Lines 14-16 (1-based)
 	}
 
-	testFuncs, nonTestLines := separateTestAndNonTestContent(lines, testFuncPos)
+	commentStarts := map[int]int{
+		5:  3,  // After the import line
+		13: 12, // After regularFunc
+	}
+
+	testFuncs, nonTestLines := separateTestAndNonTestContent(lines, testFuncPos, commentStarts, nil, Options{})
 
 	// Check test functions
 	require.Len(t, testFuncs, 2)
@@ -0,0 +1,88 @@
+package reorderfuncs
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// lineLayout locates the top-level declarations in the lines of a source.
+type lineLayout struct {
+	// inner holds the 0-based lines lying inside a declaration or a comment
+	// group, such as the blank lines of a function body, whose spacing must
+	// never change.
+	inner map[int]bool
+	// ends holds the 0-based last lines of the package clause and of the
+	// declarations, including their trailing comment, which are followed by
+	// blank lines.
+	ends map[int]bool
+}
+
+// ============================================================================
+//  Private Functions (ABC Order)
+// ============================================================================
+
+// buildLineLayout locates the top-level declarations in the lines. It returns
+// false if the lines do not parse.
+func buildLineLayout(lines []string) (lineLayout, bool) {
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "", strings.Join(lines, "\n"), parser.ParseComments)
+	if err != nil {
+		return lineLayout{}, false
+	}
+
+	layout := lineLayout{inner: make(map[int]bool), ends: make(map[int]bool)}
+	markInner := func(node ast.Node) {
+		// The lines in between the 1-based first and last lines of the node
+		for line := fset.Position(node.Pos()).Line; line < fset.Position(node.End()).Line-1; line++ {
+			layout.inner[line] = true
+		}
+	}
+
+	layout.ends[endLineWithComments(file, fset, fset.Position(file.Name.End()).Line)-1] = true
+
+	for _, decl := range file.Decls {
+		markInner(decl)
+		layout.ends[endLineWithComments(file, fset, fset.Position(decl.End()).Line)-1] = true
+	}
+
+	for _, group := range file.Comments {
+		markInner(group)
+	}
+
+	return layout, true
+}
+
+// normalizeBlankLines writes exactly n blank lines between the package clause,
+// the top-level declarations and the comments, replacing each run of blank
+// lines between them and separating the adjacent declarations. The lines
+// inside the declarations and the comments are left untouched. The lines are
+// returned as is if they do not parse.
+func normalizeBlankLines(lines []string, n int) []string {
+	layout, ok := buildLineLayout(lines)
+	if !ok {
+		return lines
+	}
+
+	outputLines := make([]string, 0, len(lines))
+	separate := false
+
+	for index, line := range lines {
+		if strings.TrimSpace(line) == "" && !layout.inner[index] {
+			separate = true
+
+			continue
+		}
+
+		if separate && len(outputLines) > 0 {
+			outputLines = appendBlankLines(outputLines, n)
+		}
+
+		outputLines = append(outputLines, line)
+		separate = layout.ends[index]
+	}
+
+	return outputLines
+}
@@ -0,0 +1,82 @@
+package reorderfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+//	Private Functions (ABC Order)
+// ============================================================================
+
+func Test_buildLineLayout(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{
+		"package main",      // 0
+		"",                  // 1
+		"var x = 1 /* last", // 2
+		"value */",          // 3
+		"/*",                // 4
+		"",                  // 5
+		"*/",                // 6
+		"func helper() {",   // 7
+		"",                  // 8
+		"}",                 // 9
+	}
+
+	layout, ok := buildLineLayout(lines)
+
+	require.True(t, ok)
+	assert.Equal(t, map[int]bool{5: true, 8: true}, layout.inner)
+	assert.Equal(t, map[int]bool{0: true, 3: true, 9: true}, layout.ends)
+
+	_, ok = buildLineLayout([]string{"func helper() {}"})
+
+	assert.False(t, ok, "no package clause")
+}
+
+func Test_normalizeBlankLines(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{
+		"package main",
+		"import \"fmt\"",
+		"",
+		"",
+		"",
+		"// Comment",
+		"",
+		"",
+		"func helper() {",
+		"\tfmt.Println(`",
+		"",
+		"",
+		"`)",
+		"}",
+		"var x = 1",
+	}
+
+	assert.Equal(t, []string{
+		"package main",
+		"",
+		"import \"fmt\"",
+		"",
+		"// Comment",
+		"",
+		"func helper() {",
+		"\tfmt.Println(`",
+		"",
+		"",
+		"`)",
+		"}",
+		"",
+		"var x = 1",
+	}, normalizeBlankLines(lines, 1))
+
+	assert.Equal(t, []string{"func a() {}", "", "", "func b() {}"},
+		normalizeBlankLines([]string{"func a() {}", "", "", "func b() {}"}, 1),
+		"lines that do not parse are returned as is")
+}
@@ -0,0 +1,94 @@
+package reorderfuncs
+
+import "bytes"
+
+// utf8BOM is the UTF-8 byte order mark some editors write at the start of files.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// sourceStyle is the byte-level layout of a source file, which is restored on
+// the reordered output: the UTF-8 byte order mark, the line endings and the
+// final newline.
+type sourceStyle struct {
+	// bom is true if the source starts with a UTF-8 byte order mark.
+	bom bool
+	// crlf is true if most lines of the source end with "\r\n".
+	crlf bool
+	// finalNewline is true if the source ends with a newline.
+	finalNewline bool
+}
+
+// ============================================================================
+//  Private Functions (ABC Order)
+// ============================================================================
+
+// detectStyle returns the byte-level layout of the source. An empty source is
+// considered to end with a newline.
+func detectStyle(src []byte) sourceStyle {
+	lineFeeds := bytes.Count(src, []byte("\n"))
+	crlfs := bytes.Count(src, []byte("\r\n"))
+
+	return sourceStyle{
+		bom:          bytes.HasPrefix(src, utf8BOM),
+		crlf:         crlfs > lineFeeds-crlfs,
+		finalNewline: len(src) == 0 || bytes.HasSuffix(src, []byte("\n")),
+	}
+}
+
+// normalize removes the byte order mark and, if most lines end with "\r\n",
+// swaps the line endings so that the source is processed line by line. The
+// lines ending with a lone "\n" then keep a trailing "\r", as the "\r\n" lines
+// of a source with mostly "\n" line endings do, which moves along with them.
+func (s sourceStyle) normalize(src []byte) []byte {
+	src = bytes.TrimPrefix(src, utf8BOM)
+
+	if s.crlf {
+		src = swapLineEndings(src)
+	}
+
+	return src
+}
+
+// restore applies the byte-level layout to the output, which has "\n" line
+// endings and a single final newline. The lines written with the majority line
+// ending, such as the blank lines and the banners, get "\r\n" if most lines of
+// the source end with it.
+func (s sourceStyle) restore(output []byte) []byte {
+	if !s.finalNewline {
+		output = bytes.TrimSuffix(output, []byte("\n"))
+	}
+
+	if s.crlf {
+		output = swapLineEndings(output)
+	}
+
+	if s.bom {
+		output = append(bytes.Clone(utf8BOM), output...)
+	}
+
+	return output
+}
+
+// swapLineEndings converts the "\r\n" line endings to "\n" and the lone "\n"
+// line endings to "\r\n".
+func swapLineEndings(src []byte) []byte {
+	swapped := make([]byte, 0, len(src)+bytes.Count(src, []byte("\n")))
+
+	for line := range bytes.SplitAfterSeq(src, []byte("\n")) {
+		content, ok := bytes.CutSuffix(line, []byte("\n"))
+		if !ok {
+			swapped = append(swapped, content...)
+
+			continue
+		}
+
+		if trimmed, ok := bytes.CutSuffix(content, []byte("\r")); ok {
+			swapped = append(append(swapped, trimmed...), '\n')
+		} else {
+			swapped = append(append(swapped, content...), '\r', '\n')
+		}
+	}
+
+	return swapped
+}
@@ -0,0 +1,71 @@
+package reorderfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ============================================================================
+//	Private Functions (ABC Order)
+// ============================================================================
+
+func Test_detectStyle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		src    string
+		expect sourceStyle
+	}{
+		{name: "unix", src: "package a\n\nvar x = 1\n", expect: sourceStyle{finalNewline: true}},
+		{name: "windows", src: "package a\r\n\r\nvar x = 1\r\n", expect: sourceStyle{crlf: true, finalNewline: true}},
+		{name: "mostly windows", src: "package a\r\n\nvar x = 1\r\n", expect: sourceStyle{crlf: true, finalNewline: true}},
+		{name: "mostly unix", src: "package a\n\nvar x = 1\r\n", expect: sourceStyle{finalNewline: true}},
+		{name: "byte order mark", src: "\ufeffpackage a\n", expect: sourceStyle{bom: true, finalNewline: true}},
+		{name: "no final newline", src: "package a\r\n\r\nvar x = 1", expect: sourceStyle{crlf: true}},
+		{name: "empty", src: "", expect: sourceStyle{finalNewline: true}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expect, detectStyle([]byte(test.src)))
+		})
+	}
+}
+
+func Test_sourceStyle_normalize(t *testing.T) {
+	t.Parallel()
+
+	src := []byte("\ufeffpackage a\r\n\r\nvar s = `\r`\r\n")
+
+	assert.Equal(t, "package a\n\nvar s = `\r`\n", string(detectStyle(src).normalize(src)))
+	assert.Equal(t, "package a\n", string(sourceStyle{}.normalize([]byte("package a\n"))))
+
+	mixed := []byte("package a\r\n\nvar x = 1\r\n")
+	assert.Equal(t, "package a\n\r\nvar x = 1\n", string(detectStyle(mixed).normalize(mixed)),
+		"the lone line feed is kept as a trailing carriage return")
+}
+
+func Test_sourceStyle_restore(t *testing.T) {
+	t.Parallel()
+
+	output := []byte("package a\n\nvar x = 1\n")
+
+	assert.Equal(t, "package a\n\nvar x = 1\n", string(sourceStyle{finalNewline: true}.restore(output)))
+	assert.Equal(t, "\ufeffpackage a\r\n\r\nvar x = 1",
+		string(sourceStyle{bom: true, crlf: true}.restore(output)))
+
+	assert.Equal(t, "package a\r\n\nvar x = 1\r\n",
+		string(sourceStyle{crlf: true, finalNewline: true}.restore([]byte("package a\n\r\nvar x = 1\n"))))
+}
+
+func Test_swapLineEndings(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "a\nb\r\nc", string(swapLineEndings([]byte("a\r\nb\nc"))))
+	assert.Equal(t, "a\r\nb\nc", string(swapLineEndings(swapLineEndings([]byte("a\r\nb\nc")))))
+	assert.Empty(t, swapLineEndings(nil))
+}
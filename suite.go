@@ -0,0 +1,176 @@
+package reorderfuncs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ============================================================================
+//  Private Functions (ABC Order)
+// ============================================================================
+
+// buildSuiteNames maps the identity of the test functions to the receiver type
+// of the testify suite they belong to. Methods belong to the suite of their
+// receiver and runner functions, such as TestFooSuite, to the suite they run.
+func buildSuiteNames(file *ast.File, fset *token.FileSet, testFuncPos map[string][2]int) map[string]string {
+	suites := make(map[string]string)
+
+	// Suites are the receiver types of the methods being reordered
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil {
+			continue
+		}
+
+		if _, ok := testFuncPos[funcKey(fn, fset)]; ok {
+			suites[funcKey(fn, fset)] = receiverTypeName(fn)
+		}
+	}
+
+	suiteTypes := make(map[string]bool, len(suites))
+	for _, suite := range suites {
+		suiteTypes[suite] = true
+	}
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil {
+			continue
+		}
+
+		if _, ok := testFuncPos[funcKey(fn, fset)]; !ok {
+			continue
+		}
+
+		if suite := findRunSuite(fn, suiteTypes); suite != "" {
+			suites[funcKey(fn, fset)] = suite
+		}
+	}
+
+	return suites
+}
+
+// findRunSuite returns the suite type run by the function. The runner is found
+// by its name, such as TestFooSuite for FooSuite, or else by a reference to the
+// suite type in its body, such as suite.Run(t, new(FooSuite)).
+func findRunSuite(fn *ast.FuncDecl, suiteTypes map[string]bool) string {
+	for _, suite := range slices.Sorted(maps.Keys(suiteTypes)) {
+		if strings.EqualFold(fn.Name.Name, testFuncPrefix+suite) {
+			return suite
+		}
+	}
+
+	if fn.Body == nil {
+		return ""
+	}
+
+	found := ""
+
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		ident, ok := node.(*ast.Ident)
+		if ok && found == "" && suiteTypes[ident.Name] {
+			found = ident.Name
+		}
+
+		return found == ""
+	})
+
+	return found
+}
+
+// funcIdentity returns the identity of a function declaration: its name, or
+// "Receiver.Name" for methods, so that methods with the same name on different
+// receivers never collide.
+func funcIdentity(fn *ast.FuncDecl) string {
+	if fn.Recv == nil {
+		return fn.Name.Name
+	}
+
+	return receiverTypeName(fn) + "." + fn.Name.Name
+}
+
+// funcKey returns the key of a function declaration in the test function
+// positions: its identity, followed by its line for the functions that may be
+// declared several times, such as "init 12" or "_ 15".
+func funcKey(fn *ast.FuncDecl, fset *token.FileSet) string {
+	if fn.Name.Name == initFuncName || fn.Name.Name == "_" {
+		return fmt.Sprintf("%s %d", funcIdentity(fn), fset.Position(fn.Pos()).Line)
+	}
+
+	return funcIdentity(fn)
+}
+
+// isSuiteMethod reports whether the function is a testify suite test method,
+// such as func (s *FooSuite) TestXxx(), with no parameters nor results.
+func isSuiteMethod(fn *ast.FuncDecl) bool {
+	if fn.Recv == nil || !hasKindName(fn.Name.Name, testFuncPrefix) {
+		return false
+	}
+
+	if fn.Type.Results != nil && len(fn.Type.Results.List) > 0 {
+		return false
+	}
+
+	return len(fn.Type.Params.List) == 0
+}
+
+// receiverTypeName returns the base type name of the method receiver, without
+// pointer and type parameters. It returns an empty string for functions.
+func receiverTypeName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+
+	return baseTypeName(fn.Recv.List[0].Type)
+}
+
+// splitIdentity splits a function identity into the receiver type name, empty
+// for functions, and the function name.
+func splitIdentity(identity string) (string, string) {
+	receiver, name, ok := strings.Cut(identity, ".")
+	if !ok {
+		return "", identity
+	}
+
+	return receiver, name
+}
+
+// suiteGroup returns the name of the sort group of the function and its rank in
+// the group. Suite runners and the methods of their suite share the group
+// named after the runner, with the runner first. Other functions form their
+// own group.
+func suiteGroup(testFunc TestFunction, runners map[string]string) (string, int) {
+	if testFunc.Suite == "" {
+		return testFunc.Name, 0
+	}
+
+	group := testFunc.Suite
+	if runner, ok := runners[testFunc.Suite]; ok {
+		group = runner
+	}
+
+	if testFunc.Receiver == "" {
+		return group, 0 // Runner first
+	}
+
+	return group, 1
+}
+
+// suiteRunners maps the suite types to the name of their runner function.
+func suiteRunners(testFuncs []TestFunction) map[string]string {
+	runners := make(map[string]string)
+
+	for _, testFunc := range testFuncs {
+		if testFunc.Suite != "" && testFunc.Receiver == "" {
+			if _, ok := runners[testFunc.Suite]; !ok {
+				runners[testFunc.Suite] = testFunc.Name
+			}
+		}
+	}
+
+	return runners
+}
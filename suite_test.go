@@ -0,0 +1,201 @@
+package reorderfuncs
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+//	Helpers
+// ============================================================================
+
+// parseFuncDecl parses a single function declaration.
+func parseFuncDecl(t *testing.T, decl string) *ast.FuncDecl {
+	t.Helper()
+
+	file, err := parser.ParseFile(token.NewFileSet(), "decl.go", "package x\n"+decl, 0)
+	require.NoError(t, err)
+
+	fn, ok := file.Decls[0].(*ast.FuncDecl)
+	require.True(t, ok, "declaration should be a function")
+
+	return fn
+}
+
+// ============================================================================
+//	Private Functions (ABC Order)
+// ============================================================================
+
+func Test_buildSuiteNames(t *testing.T) {
+	t.Parallel()
+
+	source := `package x
+
+func (s *FooSuite) TestCreate() {}
+
+func (s *BarSuite) TestCreate() {}
+
+func TestFooSuite(t *testing.T) { suite.Run(t, new(FooSuite)) }
+
+func TestBars(t *testing.T) { suite.Run(t, &BarSuite{}) }
+
+func TestPlain(t *testing.T) {}
+`
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "suite_test.go", source, 0)
+	require.NoError(t, err)
+
+	testFuncPos := buildTestFunctionPositions(file, fset, Options{})
+	suites := buildSuiteNames(file, fset, testFuncPos)
+
+	expect := map[string]string{
+		"FooSuite.TestCreate": "FooSuite",
+		"BarSuite.TestCreate": "BarSuite",
+		"TestFooSuite":        "FooSuite",
+		"TestBars":            "BarSuite",
+	}
+	assert.Equal(t, expect, suites)
+}
+
+func Test_findRunSuite(t *testing.T) {
+	t.Parallel()
+
+	suiteTypes := map[string]bool{"FooSuite": true, "barSuite": true}
+
+	tests := []struct {
+		name   string
+		decl   string
+		expect string
+	}{
+		{name: "runner by name", decl: "func TestFooSuite(t *testing.T) {}", expect: "FooSuite"},
+		{name: "runner by name of unexported suite", decl: "func TestBarSuite(t *testing.T) {}", expect: "barSuite"},
+		{name: "runner by reference", decl: "func TestFoo(t *testing.T) { suite.Run(t, new(FooSuite)) }", expect: "FooSuite"},
+		{name: "not a runner", decl: "func TestPlain(t *testing.T) { t.Log(1) }", expect: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expect, findRunSuite(parseFuncDecl(t, test.decl), suiteTypes))
+		})
+	}
+}
+
+func Test_funcIdentity(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "TestFoo", funcIdentity(parseFuncDecl(t, "func TestFoo(t *testing.T) {}")))
+	assert.Equal(t, "FooSuite.TestFoo", funcIdentity(parseFuncDecl(t, "func (s *FooSuite) TestFoo() {}")))
+
+	receiver, name := splitIdentity("FooSuite.TestFoo")
+	assert.Equal(t, "FooSuite", receiver)
+	assert.Equal(t, "TestFoo", name)
+
+	receiver, name = splitIdentity("TestFoo")
+	assert.Empty(t, receiver)
+	assert.Equal(t, "TestFoo", name)
+}
+
+func Test_funcKey(t *testing.T) {
+	t.Parallel()
+
+	source := "package x\n\nfunc init() {}\n\nfunc (Foo) _() {}\n\nfunc (Foo) Get() {}\n"
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "key.go", source, 0)
+	require.NoError(t, err)
+
+	keys := make([]string, 0, len(file.Decls))
+	for _, decl := range file.Decls {
+		keys = append(keys, funcKey(decl.(*ast.FuncDecl), fset)) //nolint:forcetypeassert // only functions
+	}
+
+	assert.Equal(t, []string{"init 3", "Foo._ 5", "Foo.Get"}, keys)
+}
+
+func Test_isSuiteMethod(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, isSuiteMethod(parseFuncDecl(t, "func (s *FooSuite) TestFoo() {}")))
+	assert.False(t, isSuiteMethod(parseFuncDecl(t, "func (s *FooSuite) SetupTest() {}")))
+	assert.False(t, isSuiteMethod(parseFuncDecl(t, "func (s *FooSuite) Testify() {}")))
+	assert.False(t, isSuiteMethod(parseFuncDecl(t, "func (s *FooSuite) TestFoo(x int) {}")))
+	assert.False(t, isSuiteMethod(parseFuncDecl(t, "func (s *FooSuite) TestFoo() error { return nil }")))
+	assert.False(t, isSuiteMethod(parseFuncDecl(t, "func TestFoo() {}")))
+}
+
+func Test_receiverTypeName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		decl   string
+		expect string
+	}{
+		{decl: "func (s *FooSuite) M() {}", expect: "FooSuite"},
+		{decl: "func (s FooSuite) M() {}", expect: "FooSuite"},
+		{decl: "func (s *Generic[T]) M() {}", expect: "Generic"},
+		{decl: "func (s *Pair[K, V]) M() {}", expect: "Pair"},
+		{decl: "func (s *(Paren)) M() {}", expect: "Paren"},
+		{decl: "func F() {}", expect: ""},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expect, receiverTypeName(parseFuncDecl(t, test.decl)), test.decl)
+	}
+}
+
+func Test_suiteGroup(t *testing.T) {
+	t.Parallel()
+
+	runners := map[string]string{"FooSuite": "TestFooSuite"}
+
+	tests := []struct {
+		name       string
+		testFunc   TestFunction
+		expectName string
+		expectRank int
+	}{
+		{
+			name:       "plain test",
+			testFunc:   TestFunction{Name: "TestPlain"},
+			expectName: "TestPlain",
+			expectRank: 0,
+		},
+		{
+			name:       "runner",
+			testFunc:   TestFunction{Name: "TestFooSuite", Suite: "FooSuite"},
+			expectName: "TestFooSuite",
+			expectRank: 0,
+		},
+		{
+			name:       "suite method",
+			testFunc:   TestFunction{Name: "TestCreate", Receiver: "FooSuite", Suite: "FooSuite"},
+			expectName: "TestFooSuite",
+			expectRank: 1,
+		},
+		{
+			name:       "suite method without runner",
+			testFunc:   TestFunction{Name: "TestCreate", Receiver: "BarSuite", Suite: "BarSuite"},
+			expectName: "BarSuite",
+			expectRank: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			name, rank := suiteGroup(test.testFunc, runners)
+
+			assert.Equal(t, test.expectName, name)
+			assert.Equal(t, test.expectRank, rank)
+		})
+	}
+}
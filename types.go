@@ -0,0 +1,154 @@
+package reorderfuncs
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// constructorPrefix is the name prefix of the constructors grouped with the
+// type they return.
+const constructorPrefix = "New"
+
+// Ranks of the declarations in the group of a type with ModeTypes.
+const (
+	rankTypeDecl = iota
+	rankConstructor
+	rankExportedMethod
+	rankUnexportedMethod
+)
+
+// ============================================================================
+//  Private Functions (ABC Order)
+// ============================================================================
+
+// baseTypeName returns the name of the type expression, without pointer, type
+// parameters nor parentheses. It returns an empty string for other types, such
+// as slices or types of other packages.
+func baseTypeName(expr ast.Expr) string {
+	for {
+		switch typ := expr.(type) {
+		case *ast.StarExpr:
+			expr = typ.X
+		case *ast.ParenExpr:
+			expr = typ.X
+		case *ast.IndexExpr:
+			expr = typ.X
+		case *ast.IndexListExpr:
+			expr = typ.X
+		case *ast.Ident:
+			return typ.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// buildTypeNames maps the identity of the declarations to the type they belong
+// to with ModeTypes: type declarations to their type, methods to their
+// receiver type and constructors to the type they return. It returns an empty
+// map with the other modes.
+func buildTypeNames(
+	file *ast.File,
+	fset *token.FileSet,
+	testFuncPos map[string][2]int,
+	options Options,
+) map[string]string {
+	types := make(map[string]string)
+	if options.Mode != ModeTypes {
+		return types
+	}
+
+	declared := declaredTypes(file)
+
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			if decl.Tok == token.TYPE && len(decl.Specs) > 0 {
+				types[typeDeclName(decl)] = typeDeclName(decl)
+			}
+		case *ast.FuncDecl:
+			if _, ok := testFuncPos[funcKey(decl, fset)]; !ok {
+				continue
+			}
+
+			if decl.Recv != nil {
+				types[funcKey(decl, fset)] = receiverTypeName(decl)
+			} else if typeName := constructorType(decl, declared); typeName != "" {
+				types[funcKey(decl, fset)] = typeName
+			}
+		}
+	}
+
+	return types
+}
+
+// constructorType returns the type built by the constructor, such as "Foo" for
+// func NewFoo() *Foo, when it is one of the declared types. It returns an empty
+// string if the function is not a constructor.
+func constructorType(fn *ast.FuncDecl, declared map[string]bool) string {
+	if fn.Recv != nil || !strings.HasPrefix(fn.Name.Name, constructorPrefix) {
+		return ""
+	}
+
+	if fn.Type.Results == nil || len(fn.Type.Results.List) == 0 {
+		return ""
+	}
+
+	typeName := baseTypeName(fn.Type.Results.List[0].Type)
+	if !declared[typeName] {
+		return ""
+	}
+
+	return typeName
+}
+
+// declaredTypes returns the names of the types declared at the top level of
+// the file.
+func declaredTypes(file *ast.File) map[string]bool {
+	declared := make(map[string]bool)
+
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+				declared[typeSpec.Name.Name] = true
+			}
+		}
+	}
+
+	return declared
+}
+
+// typeDeclName returns the name of the first type of the type declaration.
+func typeDeclName(decl *ast.GenDecl) string {
+	typeSpec, ok := decl.Specs[0].(*ast.TypeSpec)
+	if !ok {
+		return ""
+	}
+
+	return typeSpec.Name.Name
+}
+
+// typeGroup returns the name of the sort group of the declaration and its rank
+// in the group with ModeTypes. A type is followed by its constructors, its
+// exported methods and then its unexported methods. Free functions form their
+// own group.
+func typeGroup(testFunc TestFunction) (string, int) {
+	switch {
+	case testFunc.Type == "":
+		return testFunc.Name, 0
+	case testFunc.Receiver != "" && token.IsExported(testFunc.Name):
+		return testFunc.Type, rankExportedMethod
+	case testFunc.Receiver != "":
+		return testFunc.Type, rankUnexportedMethod
+	case testFunc.Name == testFunc.Type:
+		return testFunc.Type, rankTypeDecl
+	default:
+		return testFunc.Type, rankConstructor
+	}
+}
@@ -0,0 +1,146 @@
+package reorderfuncs
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+//	Private Functions (ABC Order)
+// ============================================================================
+
+func Test_baseTypeName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		expr   string
+		expect string
+	}{
+		{name: "ident", expr: "Foo", expect: "Foo"},
+		{name: "pointer", expr: "*Foo", expect: "Foo"},
+		{name: "generic", expr: "*Foo[K, V]", expect: "Foo"},
+		{name: "parenthesized", expr: "(*Foo[T])", expect: "Foo"},
+		{name: "other package", expr: "bytes.Buffer", expect: ""},
+		{name: "slice", expr: "[]Foo", expect: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := parser.ParseExpr(test.expr)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expect, baseTypeName(expr))
+		})
+	}
+}
+
+func Test_buildTypeNames(t *testing.T) {
+	t.Parallel()
+
+	source := `package x
+
+type Foo struct{}
+
+type (
+	Bar int
+	Baz int
+)
+
+func NewFoo() *Foo { return nil }
+
+func (f *Foo) Get() {}
+
+func (b Baz) String() string { return "" }
+
+func NewBuffer() *bytes.Buffer { return nil }
+
+func helper() {}
+`
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "types.go", source, 0)
+	require.NoError(t, err)
+
+	options := newOptions(WithMode(ModeTypes))
+	testFuncPos := buildTestFunctionPositions(file, fset, options)
+
+	assert.Equal(t, map[string]string{
+		"Foo":        "Foo",
+		"Bar":        "Bar",
+		"NewFoo":     "Foo",
+		"Foo.Get":    "Foo",
+		"Baz.String": "Baz",
+	}, buildTypeNames(file, fset, testFuncPos, options))
+	assert.Empty(t, buildTypeNames(file, fset, testFuncPos, newOptions(WithMode(ModeFuncs))))
+}
+
+func Test_constructorType(t *testing.T) {
+	t.Parallel()
+
+	declared := map[string]bool{"Foo": true}
+
+	assert.Equal(t, "Foo", constructorType(parseFuncDecl(t, "func NewFoo() *Foo { return nil }"), declared))
+	assert.Equal(t, "Foo", constructorType(parseFuncDecl(t, "func NewFooWithName(n string) (Foo, error) {}"), declared))
+	assert.Empty(t, constructorType(parseFuncDecl(t, "func MakeFoo() *Foo { return nil }"), declared),
+		"constructors start with New")
+	assert.Empty(t, constructorType(parseFuncDecl(t, "func NewBar() *Bar { return nil }"), declared),
+		"the type must be declared in the file")
+	assert.Empty(t, constructorType(parseFuncDecl(t, "func NewFoo() {}"), declared))
+	assert.Empty(t, constructorType(parseFuncDecl(t, "func (f Foo) NewFoo() Foo { return f }"), declared))
+}
+
+func Test_typeDeclName(t *testing.T) {
+	t.Parallel()
+
+	file, err := parser.ParseFile(token.NewFileSet(), "types.go", "package x\ntype (\n\tA int\n\tB int\n)", 0)
+	require.NoError(t, err)
+
+	decl, ok := file.Decls[0].(*ast.GenDecl)
+	require.True(t, ok)
+
+	assert.Equal(t, "A", typeDeclName(decl))
+}
+
+func Test_typeGroup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		testFunc   TestFunction
+		expectName string
+		expectRank int
+	}{
+		{name: "type", testFunc: TestFunction{Name: "Foo", Type: "Foo"}, expectName: "Foo", expectRank: 0},
+		{name: "constructor", testFunc: TestFunction{Name: "NewFoo", Type: "Foo"}, expectName: "Foo", expectRank: 1},
+		{
+			name:       "exported method",
+			testFunc:   TestFunction{Name: "Get", Receiver: "Foo", Type: "Foo"},
+			expectName: "Foo", expectRank: 2,
+		},
+		{
+			name:       "unexported method",
+			testFunc:   TestFunction{Name: "lock", Receiver: "Foo", Type: "Foo"},
+			expectName: "Foo", expectRank: 3,
+		},
+		{name: "free function", testFunc: TestFunction{Name: "helper"}, expectName: "helper", expectRank: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			name, rank := typeGroup(test.testFunc)
+
+			assert.Equal(t, test.expectName, name)
+			assert.Equal(t, test.expectRank, rank)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package reorderfuncs
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ErrContentMismatch is returned when the reordered output does not preserve
+// the content of the source, such as a dropped or duplicated line. Nothing is
+// written then.
+var ErrContentMismatch = errors.New("reordered output does not preserve the source")
+
+// ============================================================================
+//  Private Functions (ABC Order)
+// ============================================================================
+
+// bannerLineCounts counts the non-blank lines of the section banners found in
+// the lines, see findBanners.
+func bannerLineCounts(lines []string, options Options) map[string]int {
+	counts := make(map[string]int)
+
+	for _, title := range findBanners(lines, options) {
+		for _, line := range bannerComment(title, options) {
+			if strings.TrimSpace(line) != "" {
+				counts[line]++
+			}
+		}
+	}
+
+	return counts
+}
+
+// contentLines counts the non-blank lines. With Options.Banners, the lines of
+// the section banners, see findBanners, are counted apart and subtracted since
+// they are removed and regenerated: a comment that is not a banner must be
+// found as many times in the output as in the source.
+func contentLines(lines []string, options Options) map[string]int {
+	counts := make(map[string]int, len(lines))
+
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			counts[line]++
+		}
+	}
+
+	if options.Banners {
+		for line, count := range bannerLineCounts(lines, options) {
+			if counts[line] -= count; counts[line] == 0 {
+				delete(counts, line)
+			}
+		}
+	}
+
+	return counts
+}
+
+// declBodies maps the identity of each top-level declaration to the source of
+// its function bodies, one per declaration with that identity. General
+// declarations have no body, see genDeclNames.
+func declBodies(file *ast.File, src []byte, fset *token.FileSet) map[string][]string {
+	bodies := make(map[string][]string, len(file.Decls))
+
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			body := ""
+			if decl.Body != nil {
+				body = string(src[fset.Position(decl.Body.Pos()).Offset:fset.Position(decl.Body.End()).Offset])
+			}
+
+			bodies[funcIdentity(decl)] = append(bodies[funcIdentity(decl)], body)
+		case *ast.GenDecl:
+			bodies[genDeclNames(decl)] = append(bodies[genDeclNames(decl)], "")
+		}
+	}
+
+	for identity := range bodies {
+		slices.Sort(bodies[identity])
+	}
+
+	return bodies
+}
+
+// diffCounts returns the first key, in sorted order, whose count differs, and
+// its counts in a and b.
+func diffCounts(a, b map[string]int) (string, int, int, bool) {
+	keys := slices.AppendSeq(slices.Collect(maps.Keys(a)), maps.Keys(b))
+	slices.Sort(keys)
+
+	for _, key := range slices.Compact(keys) {
+		if a[key] != b[key] {
+			return key, a[key], b[key], true
+		}
+	}
+
+	return "", 0, 0, false
+}
+
+// genDeclNames returns the identity of the general declaration: its keyword
+// and sorted names, such as "var a,b", since SortDecls may reorder its specs.
+func genDeclNames(decl *ast.GenDecl) string {
+	return decl.Tok.String() + " " + strings.Join(slices.Sorted(slices.Values(specNames(decl))), ",")
+}
+
+// specNames returns the names declared by the specs of the general declaration,
+// or the paths of the imports.
+func specNames(decl *ast.GenDecl) []string {
+	var names []string
+
+	for _, spec := range decl.Specs {
+		switch spec := spec.(type) {
+		case *ast.ImportSpec:
+			names = append(names, spec.Path.Value)
+		case *ast.TypeSpec:
+			names = append(names, spec.Name.Name)
+		case *ast.ValueSpec:
+			for _, name := range spec.Names {
+				names = append(names, name.Name)
+			}
+		}
+	}
+
+	return names
+}
+
+// verifyOutput checks that the reordered output preserves the content of the
+// source: the output parses, it has the same declarations, each function body
+// is byte-identical and the non-blank lines are the same, only reordered. It
+// returns an error wrapping ErrContentMismatch describing the first difference.
+func verifyOutput(filename string, src, output []byte, options Options) error {
+	srcFset := token.NewFileSet()
+
+	srcFile, err := parser.ParseFile(srcFset, filename, src, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("failed to parse Go file: %w", err)
+	}
+
+	outFset := token.NewFileSet()
+
+	outFile, err := parser.ParseFile(outFset, filename, output, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("%w: %s: output does not parse: %w", ErrContentMismatch, filename, err)
+	}
+
+	srcBodies := declBodies(srcFile, src, srcFset)
+	outBodies := declBodies(outFile, output, outFset)
+
+	for _, identity := range slices.Sorted(maps.Keys(srcBodies)) {
+		if len(srcBodies[identity]) != len(outBodies[identity]) {
+			return fmt.Errorf("%w: %s: declaration %s found %d times instead of %d",
+				ErrContentMismatch, filename, identity, len(outBodies[identity]), len(srcBodies[identity]))
+		}
+
+		if !slices.Equal(srcBodies[identity], outBodies[identity]) {
+			return fmt.Errorf("%w: %s: body of %s changed", ErrContentMismatch, filename, identity)
+		}
+	}
+
+	for _, identity := range slices.Sorted(maps.Keys(outBodies)) {
+		if _, ok := srcBodies[identity]; !ok {
+			return fmt.Errorf("%w: %s: unexpected declaration %s", ErrContentMismatch, filename, identity)
+		}
+	}
+
+	srcLines := contentLines(strings.Split(string(src), "\n"), options)
+	outLines := contentLines(strings.Split(string(output), "\n"), options)
+
+	if line, want, got, ok := diffCounts(srcLines, outLines); ok {
+		return fmt.Errorf("%w: %s: line %q found %d times instead of %d",
+			ErrContentMismatch, filename, line, got, want)
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package reorderfuncs
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+//	Private Functions (ABC Order)
+// ============================================================================
+
+func Test_bannerLineCounts(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{"package main", "", "// ==", "//  Tests", "// ==", "", "// ==", "//  Helpers", "// ==", ""}
+
+	assert.Equal(t, map[string]int{"// ==": 4, "//  Tests": 1, "//  Helpers": 1},
+		bannerLineCounts(lines, newOptions(WithBanners("// ==\n//  %s\n// =="))))
+	assert.Empty(t, bannerLineCounts(lines, newOptions(WithBanners("// %s"))))
+}
+
+func Test_contentLines(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{"package main", "", "// ==", "//  Tests", "// ==", "", "func TestA() {}", "func TestA() {}"}
+
+	assert.Equal(t, map[string]int{
+		"package main": 1, "// ==": 2, "//  Tests": 1, "func TestA() {}": 2,
+	}, contentLines(lines, Options{}))
+	assert.Equal(t, map[string]int{"package main": 1, "func TestA() {}": 2},
+		contentLines(lines, newOptions(WithBanners("// ==\n//  %s\n// =="))), "banners are ignored")
+
+	comments := []string{
+		"package main", "", "// Tests", "", "// TestA checks A.", "func TestA() {", "// Tests", "}", "", "// Tests ", "",
+	}
+
+	assert.Equal(t, map[string]int{
+		"package main": 1, "// TestA checks A.": 1, "func TestA() {": 1, "// Tests": 1, "}": 1, "// Tests ": 1,
+	}, contentLines(comments, newOptions(WithBanners("// %s"))), "only the standalone banners are counted apart")
+}
+
+func Test_declBodies(t *testing.T) {
+	t.Parallel()
+
+	src := []byte("package main\n\nimport \"fmt\"\n\nvar (\n\tb = 1\n\ta = 2\n)\n\n" +
+		"func init() { fmt.Println(1) }\n\nfunc init() {}\n\nfunc (s *Foo) Get() {}\n")
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "main.go", src, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]string{
+		`import "fmt"`: {""},
+		"var a,b":      {""},
+		"init":         {"{ fmt.Println(1) }", "{}"},
+		"Foo.Get":      {"{}"},
+	}, declBodies(file, src, fset))
+}
+
+func Test_diffCounts(t *testing.T) {
+	t.Parallel()
+
+	_, _, _, ok := diffCounts(map[string]int{"a": 1}, map[string]int{"a": 1})
+
+	assert.False(t, ok)
+
+	key, countA, countB, ok := diffCounts(map[string]int{"a": 1, "b": 1}, map[string]int{"a": 1, "c": 2})
+
+	assert.True(t, ok)
+	assert.Equal(t, "b", key)
+	assert.Equal(t, 1, countA)
+	assert.Equal(t, 0, countB)
+}
+
+func Test_verifyOutput(t *testing.T) {
+	t.Parallel()
+
+	src := "package main\n\n// TestB doc.\nfunc TestB() {\n\tprintln(1)\n}\n\nfunc TestA() {}\n"
+
+	tests := []struct {
+		name   string
+		output string
+		expect string
+	}{
+		{
+			name:   "reordered",
+			output: "package main\n\nfunc TestA() {}\n\n// TestB doc.\nfunc TestB() {\n\tprintln(1)\n}\n",
+		},
+		{
+			name:   "output does not parse",
+			output: "package main\n\nfunc TestA() {\n",
+			expect: "output does not parse",
+		},
+		{
+			name:   "dropped function",
+			output: "package main\n\nfunc TestA() {}\n",
+			expect: "declaration TestB found 0 times instead of 1",
+		},
+		{
+			name:   "duplicated function",
+			output: "package main\n\nfunc TestA() {}\n\nfunc TestA() {}\n\n// TestB doc.\nfunc TestB() {\n\tprintln(1)\n}\n",
+			expect: "declaration TestA found 2 times instead of 1",
+		},
+		{
+			name:   "changed body",
+			output: "package main\n\nfunc TestA() {}\n\n// TestB doc.\nfunc TestB() {\n\tprintln(2)\n}\n",
+			expect: "body of TestB changed",
+		},
+		{
+			name:   "unexpected declaration",
+			output: "package main\n\nfunc TestA() {}\n\n// TestB doc.\nfunc TestB() {\n\tprintln(1)\n}\n\nvar x = 1\n",
+			expect: "unexpected declaration var x",
+		},
+		{
+			name:   "dropped comment",
+			output: "package main\n\nfunc TestA() {}\n\nfunc TestB() {\n\tprintln(1)\n}\n",
+			expect: `line "// TestB doc." found 0 times instead of 1`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := verifyOutput("main.go", []byte(src), []byte(test.output), Options{})
+			if test.expect == "" {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.ErrorIs(t, err, ErrContentMismatch)
+			assert.Contains(t, err.Error(), test.expect)
+		})
+	}
+}
+
+func Test_verifyOutput_banners(t *testing.T) {
+	t.Parallel()
+
+	src := "package main\n\n// Examples\nvar registry = 1\n\n// Tests\n\nfunc TestB() {}\n\nfunc TestA() {}\n"
+	options := newOptions(WithBanners("// %s"))
+
+	err := verifyOutput("main.go", []byte(src),
+		[]byte("package main\n\n// Examples\nvar registry = 1\n\n// Tests\n\nfunc TestA() {}\n\nfunc TestB() {}\n"), options)
+	require.NoError(t, err, "the banner is regenerated")
+
+	err = verifyOutput("main.go", []byte(src),
+		[]byte("package main\n\nvar registry = 1\n\n// Tests\n\nfunc TestA() {}\n\nfunc TestB() {}\n"), options)
+	require.ErrorIs(t, err, ErrContentMismatch, "a doc comment is not a banner")
+	assert.Contains(t, err.Error(), `line "// Examples" found 0 times instead of 1`)
+}